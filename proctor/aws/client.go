@@ -0,0 +1,31 @@
+// Package aws implements the iaas.Client contract on top of Amazon Web
+// Services: CloudFormation for the infrastructure stack, EC2 key pairs for
+// keypairs, and S3 for uploaded artifacts.
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// Client talks to a single AWS account and region on behalf of Controller.
+type Client struct {
+	EC2 *ec2.EC2
+	CFN *cloudformation.CloudFormation
+	S3  *s3.S3
+
+	Bucket string
+}
+
+// NewClient builds a Client from a shared AWS session, storing uploaded
+// artifacts in bucket.
+func NewClient(sess *session.Session, bucket string) *Client {
+	return &Client{
+		EC2:    ec2.New(sess),
+		CFN:    cloudformation.New(sess),
+		S3:     s3.New(sess),
+		Bucket: bucket,
+	}
+}
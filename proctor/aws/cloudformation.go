@@ -0,0 +1,107 @@
+package aws
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// CreateStack submits template as a new CloudFormation stack named name,
+// substituting parameters, and returns the generated stack ID.
+func (c *Client) CreateStack(name, template string, parameters, tags map[string]string) (string, error) {
+	out, err := c.CFN.CreateStack(&cloudformation.CreateStackInput{
+		StackName:    aws.String(name),
+		TemplateBody: aws.String(template),
+		Parameters:   cfnParameters(parameters),
+		Tags:         cfnTags(tags),
+		Capabilities: []*string{aws.String(cloudformation.CapabilityCapabilityIam)},
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not create stack '%s': %s", name, err)
+	}
+	return aws.StringValue(out.StackId), nil
+}
+
+// DeleteStack deletes the stack named name.
+func (c *Client) DeleteStack(name string) error {
+	_, err := c.CFN.DeleteStack(&cloudformation.DeleteStackInput{StackName: aws.String(name)})
+	return err
+}
+
+// DescribeStack returns name's stack status, ID, and the parameters it was
+// last created or updated with.
+func (c *Client) DescribeStack(name string) (string, string, map[string]string, error) {
+	out, err := c.CFN.DescribeStacks(&cloudformation.DescribeStacksInput{StackName: aws.String(name)})
+	if err != nil {
+		return "", "", nil, fmt.Errorf("could not describe stack '%s': %s", name, err)
+	}
+	if len(out.Stacks) != 1 {
+		return "", "", nil, fmt.Errorf("stack '%s' not found", name)
+	}
+
+	stack := out.Stacks[0]
+	parameters := map[string]string{}
+	for _, p := range stack.Parameters {
+		parameters[aws.StringValue(p.ParameterKey)] = aws.StringValue(p.ParameterValue)
+	}
+
+	return aws.StringValue(stack.StackStatus), aws.StringValue(stack.StackId), parameters, nil
+}
+
+// GetHostsFromStackID returns the instance ID to public IP mapping for
+// the EC2 instances created by stackID's stack.
+func (c *Client) GetHostsFromStackID(stackID string) (map[string]string, error) {
+	out, err := c.CFN.DescribeStackResources(&cloudformation.DescribeStackResourcesInput{
+		StackName: aws.String(stackID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not list resources for stack '%s': %s", stackID, err)
+	}
+
+	var instanceIDs []*string
+	for _, resource := range out.StackResources {
+		if aws.StringValue(resource.ResourceType) == "AWS::EC2::Instance" {
+			instanceIDs = append(instanceIDs, resource.PhysicalResourceId)
+		}
+	}
+	if len(instanceIDs) == 0 {
+		return map[string]string{}, nil
+	}
+
+	instancesOut, err := c.EC2.DescribeInstances(&ec2.DescribeInstancesInput{InstanceIds: instanceIDs})
+	if err != nil {
+		return nil, fmt.Errorf("could not describe instances for stack '%s': %s", stackID, err)
+	}
+
+	hosts := map[string]string{}
+	for _, reservation := range instancesOut.Reservations {
+		for _, instance := range reservation.Instances {
+			if instance.PublicIpAddress == nil {
+				continue
+			}
+			hosts[aws.StringValue(instance.InstanceId)] = aws.StringValue(instance.PublicIpAddress)
+		}
+	}
+	return hosts, nil
+}
+
+func cfnParameters(parameters map[string]string) []*cloudformation.Parameter {
+	var out []*cloudformation.Parameter
+	for key, value := range parameters {
+		out = append(out, &cloudformation.Parameter{
+			ParameterKey:   aws.String(key),
+			ParameterValue: aws.String(value),
+		})
+	}
+	return out
+}
+
+func cfnTags(tags map[string]string) []*cloudformation.Tag {
+	var out []*cloudformation.Tag
+	for key, value := range tags {
+		out = append(out, &cloudformation.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	return out
+}
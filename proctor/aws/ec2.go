@@ -3,13 +3,20 @@ package aws
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/daverc1-pivotal/bosh-classroom/proctor/iaas"
 )
 
-func (c *Client) CreateKey(name string) (string, error) {
-	out, err := c.EC2.CreateKeyPair(&ec2.CreateKeyPairInput{KeyName: aws.String(name)})
+func (c *Client) CreateKey(name string, tags map[string]string) (string, error) {
+	out, err := c.EC2.CreateKeyPair(&ec2.CreateKeyPairInput{
+		KeyName:           aws.String(name),
+		TagSpecifications: keyTagSpecifications(tags),
+	})
 	if err != nil {
 		return "", err
 	}
@@ -33,3 +40,142 @@ func (c *Client) DeleteKey(name string) error {
 	_, err := c.EC2.DeleteKeyPair(&ec2.DeleteKeyPairInput{KeyName: aws.String(name)})
 	return err
 }
+
+// ImportKey registers a locally-generated public key with EC2 under name,
+// rather than asking EC2 to generate (and hand back) a new private key. The
+// caller keeps the matching private key; EC2 never sees it.
+func (c *Client) ImportKey(name string, publicKeyPEM []byte, tags map[string]string) error {
+	out, err := c.EC2.ImportKeyPair(&ec2.ImportKeyPairInput{
+		KeyName:           aws.String(name),
+		PublicKeyMaterial: publicKeyPEM,
+		TagSpecifications: keyTagSpecifications(tags),
+	})
+	if err != nil {
+		return err
+	}
+	if out.KeyName == nil || *out.KeyName != name {
+		return fmt.Errorf("tried to import key named '%s' but EC2 registered a different name", name)
+	}
+	return nil
+}
+
+// KeyFingerprint returns the fingerprint EC2 has on file for name, so a
+// locally-held key can be reconciled against what's actually registered.
+func (c *Client) KeyFingerprint(name string) (string, error) {
+	out, err := c.EC2.DescribeKeyPairs(&ec2.DescribeKeyPairsInput{
+		KeyNames: []*string{aws.String(name)},
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(out.KeyPairs) != 1 || out.KeyPairs[0].KeyFingerprint == nil {
+		return "", fmt.Errorf("could not determine EC2 fingerprint for key '%s'", name)
+	}
+	return *out.KeyPairs[0].KeyFingerprint, nil
+}
+
+// ImageAvailable reports whether image is a currently-registered AMI ID in
+// this Client's region.
+func (c *Client) ImageAvailable(image string) (bool, error) {
+	_, err := c.EC2.DescribeImages(&ec2.DescribeImagesInput{
+		ImageIds: []*string{aws.String(image)},
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "InvalidAMIID.NotFound" {
+			return false, nil
+		}
+		return false, fmt.Errorf("could not check AMI '%s': %s", image, err)
+	}
+	return true, nil
+}
+
+// ListKeys returns the names of all EC2 key pairs whose name starts with
+// prefix.
+func (c *Client) ListKeys(prefix string) ([]string, error) {
+	out, err := c.EC2.DescribeKeyPairs(&ec2.DescribeKeyPairsInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, pair := range out.KeyPairs {
+		if pair.KeyName == nil {
+			continue
+		}
+		if strings.HasPrefix(*pair.KeyName, prefix) {
+			names = append(names, *pair.KeyName)
+		}
+	}
+	return names, nil
+}
+
+// ListClassroomsByTag returns the bosh-classroom:name tag value of every
+// EC2 key pair whose tags match all of filters, analogous to how
+// DescribeKeyPairs' own Filters parameter works.
+func (c *Client) ListClassroomsByTag(filters map[string]string) ([]string, error) {
+	out, err := c.EC2.DescribeKeyPairs(&ec2.DescribeKeyPairsInput{
+		Filters: tagFilters(filters),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, pair := range out.KeyPairs {
+		for _, tag := range pair.Tags {
+			if aws.StringValue(tag.Key) == iaas.TagPrefix+"name" {
+				names = append(names, aws.StringValue(tag.Value))
+			}
+		}
+	}
+	return names, nil
+}
+
+// Tags returns the bosh-classroom tags recorded on the key pair named
+// name.
+func (c *Client) Tags(name string) (map[string]string, error) {
+	out, err := c.EC2.DescribeKeyPairs(&ec2.DescribeKeyPairsInput{
+		KeyNames: []*string{aws.String(name)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(out.KeyPairs) != 1 {
+		return nil, fmt.Errorf("could not find key pair '%s'", name)
+	}
+
+	tags := map[string]string{}
+	for _, tag := range out.KeyPairs[0].Tags {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return tags, nil
+}
+
+func tagFilters(filters map[string]string) []*ec2.Filter {
+	var out []*ec2.Filter
+	for key, value := range filters {
+		out = append(out, &ec2.Filter{
+			Name:   aws.String("tag:" + iaas.TagPrefix + key),
+			Values: []*string{aws.String(value)},
+		})
+	}
+	return out
+}
+
+func keyTagSpecifications(tags map[string]string) []*ec2.TagSpecification {
+	if len(tags) == 0 {
+		return nil
+	}
+	return []*ec2.TagSpecification{{
+		ResourceType: aws.String(ec2.ResourceTypeKeyPair),
+		Tags:         ec2Tags(tags),
+	}}
+}
+
+func ec2Tags(tags map[string]string) []*ec2.Tag {
+	var out []*ec2.Tag
+	for key, value := range tags {
+		out = append(out, &ec2.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	return out
+}
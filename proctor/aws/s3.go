@@ -0,0 +1,69 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// StoreObject uploads data to c.Bucket under name.
+func (c *Client) StoreObject(name string, data []byte, downloadFileName, contentType string, tags map[string]string) error {
+	_, err := c.S3.PutObject(&s3.PutObjectInput{
+		Bucket:             aws.String(c.Bucket),
+		Key:                aws.String(name),
+		Body:               bytes.NewReader(data),
+		ContentType:        aws.String(contentType),
+		ContentDisposition: aws.String(fmt.Sprintf("attachment; filename=%q", downloadFileName)),
+		Tagging:            s3Tagging(tags),
+	})
+	if err != nil {
+		return fmt.Errorf("could not upload '%s': %s", name, err)
+	}
+	return nil
+}
+
+// s3Tagging encodes tags as the URL-encoded "key1=value1&key2=value2"
+// query string S3's object tagging header expects.
+func s3Tagging(tags map[string]string) *string {
+	if len(tags) == 0 {
+		return nil
+	}
+	values := url.Values{}
+	for key, value := range tags {
+		values.Set(key, value)
+	}
+	encoded := strings.ReplaceAll(values.Encode(), "+", "%20")
+	return aws.String(encoded)
+}
+
+// FetchObject downloads and returns the bytes previously stored under name.
+func (c *Client) FetchObject(name string) ([]byte, error) {
+	out, err := c.S3.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(c.Bucket),
+		Key:    aws.String(name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch '%s': %s", name, err)
+	}
+	defer out.Body.Close()
+	return ioutil.ReadAll(out.Body)
+}
+
+// DeleteObject removes name from c.Bucket.
+func (c *Client) DeleteObject(name string) error {
+	_, err := c.S3.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(c.Bucket),
+		Key:    aws.String(name),
+	})
+	return err
+}
+
+// URLForObject returns the public S3 URL for name.
+func (c *Client) URLForObject(name string) string {
+	return fmt.Sprintf("https://%s.s3.amazonaws.com/%s", c.Bucket, name)
+}
@@ -0,0 +1,62 @@
+package aws
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+
+	"github.com/daverc1-pivotal/bosh-classroom/proctor/iaas"
+)
+
+// StackEventsSince returns name's stack events that occurred strictly after
+// since, oldest first, along with the timestamp of the newest event seen
+// (since is returned unchanged if there were none). CloudFormation hands
+// events back newest-first a page at a time, so this walks pages until it
+// reaches events at or before since.
+func (c *Client) StackEventsSince(name string, since time.Time) ([]iaas.StackEvent, time.Time, error) {
+	var events []iaas.StackEvent
+	newest := since
+
+	var nextToken *string
+	for {
+		out, err := c.CFN.DescribeStackEvents(&cloudformation.DescribeStackEventsInput{
+			StackName: aws.String(name),
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, since, err
+		}
+
+		reachedKnown := false
+		for _, e := range out.StackEvents {
+			if e.Timestamp == nil || !e.Timestamp.After(since) {
+				reachedKnown = true
+				break
+			}
+			events = append(events, iaas.StackEvent{
+				Timestamp:         *e.Timestamp,
+				LogicalResourceID: aws.StringValue(e.LogicalResourceId),
+				ResourceType:      aws.StringValue(e.ResourceType),
+				ResourceStatus:    aws.StringValue(e.ResourceStatus),
+				StatusReason:      aws.StringValue(e.ResourceStatusReason),
+			})
+			if e.Timestamp.After(newest) {
+				newest = *e.Timestamp
+			}
+		}
+
+		if reachedKnown || out.NextToken == nil {
+			break
+		}
+		nextToken = out.NextToken
+	}
+
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+
+	return events, newest, nil
+}
+
+var _ iaas.Client = (*Client)(nil)
@@ -0,0 +1,33 @@
+// Package azure implements the iaas.Client contract on top of Microsoft
+// Azure: ARM templates deployed into a resource group for the
+// infrastructure stack, VM SSH public keys for keypairs, and Blob Storage
+// for uploaded artifacts.
+package azure
+
+import (
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-05-01/network"
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2021-04-01/resources"
+	"github.com/Azure/azure-sdk-for-go/storage"
+)
+
+// Client talks to a single Azure subscription and resource group on
+// behalf of Controller.
+type Client struct {
+	Deployments          resources.DeploymentsClient
+	DeploymentOperations resources.DeploymentOperationsClient
+	Groups               resources.GroupsClient
+	Blobs                storage.BlobStorageClient
+
+	// VirtualMachines, Interfaces, and PublicIPAddresses resolve a
+	// deployment's VM resources down to their public IPs in
+	// GetHostsFromStackID: OutputResources only gives resource IDs, so
+	// walking VM -> NIC -> public IP each needs its own client.
+	VirtualMachines   compute.VirtualMachinesClient
+	Interfaces        network.InterfacesClient
+	PublicIPAddresses network.PublicIPAddressesClient
+
+	SubscriptionID string
+	ResourceGroup  string
+	Container      string
+}
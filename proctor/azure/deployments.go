@@ -0,0 +1,240 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2021-04-01/resources"
+
+	"github.com/daverc1-pivotal/bosh-classroom/proctor/iaas"
+)
+
+// CreateStack deploys template (an ARM template) into c.ResourceGroup under
+// name, substituting parameters. It returns name itself as the stack ID:
+// ARM deployments, unlike CloudFormation stacks, are addressed by name
+// rather than a separate generated ID.
+func (c *Client) CreateStack(name, template string, parameters, tags map[string]string) (string, error) {
+	deployment := resources.Deployment{
+		Properties: &resources.DeploymentProperties{
+			Template:   rawTemplate(template),
+			Parameters: armParameters(parameters),
+			Mode:       resources.Incremental,
+		},
+		Tags: armTags(tags),
+	}
+
+	future, err := c.Deployments.CreateOrUpdate(context.Background(), c.ResourceGroup, name, deployment)
+	if err != nil {
+		return "", fmt.Errorf("could not create deployment '%s': %s", name, err)
+	}
+	if err := future.WaitForCompletionRef(context.Background(), c.Deployments.Client); err != nil {
+		return "", fmt.Errorf("could not submit deployment '%s': %s", name, err)
+	}
+
+	return name, nil
+}
+
+// ImageAvailable always reports true: ARM templates reference VM images by
+// publisher/offer/sku/version, not a resource this Client otherwise
+// queries, and adding a full Compute client just for this one pre-flight
+// check isn't worth the dependency. ImportClassroom only falls back to a
+// fresh AtlasClient lookup when the bundle's image parameter is empty.
+func (c *Client) ImageAvailable(image string) (bool, error) {
+	return true, nil
+}
+
+// DeleteStack deletes the deployment named name from c.ResourceGroup.
+func (c *Client) DeleteStack(name string) error {
+	future, err := c.Deployments.Delete(context.Background(), c.ResourceGroup, name)
+	if err != nil {
+		return fmt.Errorf("could not delete deployment '%s': %s", name, err)
+	}
+	return future.WaitForCompletionRef(context.Background(), c.Deployments.Client)
+}
+
+// DescribeStack returns name's deployment provisioning state, its name
+// (used as the stack ID), and the parameters it was deployed with.
+func (c *Client) DescribeStack(name string) (string, string, map[string]string, error) {
+	deployment, err := c.Deployments.Get(context.Background(), c.ResourceGroup, name)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("could not describe deployment '%s': %s", name, err)
+	}
+
+	status := armStatus(deployment)
+	var parameters map[string]string
+	if deployment.Properties != nil {
+		parameters = extractARMParameters(deployment.Properties.Parameters)
+	} else {
+		parameters = map[string]string{}
+	}
+
+	return status, name, parameters, nil
+}
+
+// GetHostsFromStackID returns the VM name to public IP mapping for the
+// instances created by the deployment named stackID. ARM deployments
+// don't fold that into the deployment resource itself, so this walks each
+// VM the deployment reports as an output resource to its network
+// interface, and that interface to its public IP address.
+func (c *Client) GetHostsFromStackID(stackID string) (map[string]string, error) {
+	deployment, err := c.Deployments.Get(context.Background(), c.ResourceGroup, stackID)
+	if err != nil {
+		return nil, fmt.Errorf("could not describe deployment '%s': %s", stackID, err)
+	}
+	if deployment.Properties == nil || deployment.Properties.OutputResources == nil {
+		return map[string]string{}, nil
+	}
+
+	hosts := map[string]string{}
+	for _, resourceRef := range *deployment.Properties.OutputResources {
+		id := derefString(resourceRef.ID)
+		if !strings.Contains(id, "/Microsoft.Compute/virtualMachines/") {
+			continue
+		}
+		vmName := resourceNameFromID(id)
+
+		vm, err := c.VirtualMachines.Get(context.Background(), c.ResourceGroup, vmName, "")
+		if err != nil {
+			return nil, fmt.Errorf("could not describe VM '%s': %s", vmName, err)
+		}
+		ip, err := c.publicIPForVM(vm)
+		if err != nil {
+			return nil, err
+		}
+		if ip != "" {
+			hosts[vmName] = ip
+		}
+	}
+	return hosts, nil
+}
+
+// publicIPForVM returns the first public IP address found among vm's
+// network interfaces, or "" if it has none.
+func (c *Client) publicIPForVM(vm compute.VirtualMachine) (string, error) {
+	if vm.VirtualMachineProperties == nil || vm.NetworkProfile == nil || vm.NetworkProfile.NetworkInterfaces == nil {
+		return "", nil
+	}
+	for _, nicRef := range *vm.NetworkProfile.NetworkInterfaces {
+		nicName := resourceNameFromID(derefString(nicRef.ID))
+		if nicName == "" {
+			continue
+		}
+		nic, err := c.Interfaces.Get(context.Background(), c.ResourceGroup, nicName, "")
+		if err != nil {
+			return "", fmt.Errorf("could not describe network interface '%s': %s", nicName, err)
+		}
+		if nic.InterfacePropertiesFormat == nil || nic.IPConfigurations == nil {
+			continue
+		}
+		for _, ipConfig := range *nic.IPConfigurations {
+			if ipConfig.InterfaceIPConfigurationPropertiesFormat == nil || ipConfig.PublicIPAddress == nil {
+				continue
+			}
+			pipName := resourceNameFromID(derefString(ipConfig.PublicIPAddress.ID))
+			if pipName == "" {
+				continue
+			}
+			pip, err := c.PublicIPAddresses.Get(context.Background(), c.ResourceGroup, pipName, "")
+			if err != nil {
+				return "", fmt.Errorf("could not describe public IP '%s': %s", pipName, err)
+			}
+			if pip.PublicIPAddressPropertiesFormat != nil && pip.IPAddress != nil {
+				return *pip.IPAddress, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// resourceNameFromID returns the last path segment of an Azure resource
+// ID (e.g. ".../virtualMachines/my-vm" -> "my-vm").
+func resourceNameFromID(id string) string {
+	parts := strings.Split(id, "/")
+	return parts[len(parts)-1]
+}
+
+// StackEventsSince returns operations for the deployment named name that
+// occurred strictly after since, generalized into the same iaas.StackEvent
+// shape used for CloudFormation events.
+func (c *Client) StackEventsSince(name string, since time.Time) ([]iaas.StackEvent, time.Time, error) {
+	page, err := c.DeploymentOperations.List(context.Background(), c.ResourceGroup, name, nil)
+	if err != nil {
+		return nil, since, fmt.Errorf("could not list operations for deployment '%s': %s", name, err)
+	}
+
+	var events []iaas.StackEvent
+	newest := since
+	for ; page.NotDone(); err = page.NextWithContext(context.Background()) {
+		if err != nil {
+			break
+		}
+		for _, op := range page.Values() {
+			if op.Properties == nil || op.Properties.Timestamp == nil {
+				continue
+			}
+			ts := op.Properties.Timestamp.ToTime()
+			if !ts.After(since) {
+				continue
+			}
+			events = append(events, iaas.StackEvent{
+				Timestamp:         ts,
+				LogicalResourceID: derefString(targetResourceName(op.Properties)),
+				ResourceType:      derefString(targetResourceType(op.Properties)),
+				ResourceStatus:    derefString(op.Properties.ProvisioningState),
+				StatusReason:      armStatusMessage(op.Properties),
+			})
+			if ts.After(newest) {
+				newest = ts
+			}
+		}
+	}
+
+	return events, newest, nil
+}
+
+func targetResourceName(props *resources.DeploymentOperationProperties) *string {
+	if props.TargetResource == nil {
+		return nil
+	}
+	return props.TargetResource.ResourceName
+}
+
+func targetResourceType(props *resources.DeploymentOperationProperties) *string {
+	if props.TargetResource == nil {
+		return nil
+	}
+	return props.TargetResource.ResourceType
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func armStatusMessage(props *resources.DeploymentOperationProperties) string {
+	if props.StatusMessage == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", *props.StatusMessage)
+}
+
+func armStatus(deployment resources.DeploymentExtended) string {
+	if deployment.Properties == nil {
+		return "CREATE_IN_PROGRESS"
+	}
+	switch deployment.Properties.ProvisioningState {
+	case "Succeeded":
+		return "CREATE_COMPLETE"
+	case "Failed":
+		return "CREATE_FAILED"
+	case "Canceled":
+		return "ROLLBACK_COMPLETE"
+	default:
+		return "CREATE_IN_PROGRESS"
+	}
+}
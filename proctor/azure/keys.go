@@ -0,0 +1,245 @@
+package azure
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2021-04-01/resources"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/daverc1-pivotal/bosh-classroom/proctor/iaas"
+)
+
+// Azure has no standalone keypair resource the way EC2 does; the public
+// key is simply handed to each VM at creation time. Like the gcp package,
+// we track registered keys as tags on the resource group so ListKeys,
+// KeyFingerprint, and the import/export flow have somewhere to live.
+const keyTagPrefix = "classroom-ssh-key-"
+
+// classroomTagPrefix namespaces the resource group tags a classroom's
+// bosh-classroom tags (name, owner, created-at, ttl) are stored under,
+// one tag per name/tag pair since resource group tags are a flat map.
+const classroomTagPrefix = "classroom-tag-"
+
+// maxTagValueLength is Azure's cap on a resource tag value, far short of
+// an OpenSSH RSA public key's ~400 characters. ImportKey splits the key
+// across as many numbered tags (classroom-ssh-key-<name>/0, /1, ...) as it
+// takes; KeyFingerprint reassembles them in order.
+const maxTagValueLength = 256
+
+func classroomTagKeyFor(name, tag string) string {
+	return classroomTagPrefix + name + ":" + tag
+}
+
+func keyChunkTagFor(name string, index int) string {
+	return fmt.Sprintf("%s%s/%d", keyTagPrefix, name, index)
+}
+
+// chunk splits value into pieces of at most size characters, always
+// returning at least one piece (even for an empty value).
+func chunk(value string, size int) []string {
+	var chunks []string
+	for len(value) > size {
+		chunks = append(chunks, value[:size])
+		value = value[size:]
+	}
+	return append(chunks, value)
+}
+
+// CreateKey generates an RSA keypair locally and records the public half
+// as resource group tags named after name, returning the PEM-encoded
+// private key.
+func (c *Client) CreateKey(name string, tags map[string]string) (string, error) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return "", fmt.Errorf("could not generate keypair: %s", err)
+	}
+
+	signer, err := ssh.NewSignerFromKey(private)
+	if err != nil {
+		return "", fmt.Errorf("could not derive public key: %s", err)
+	}
+	publicKeyPEM := ssh.MarshalAuthorizedKey(signer.PublicKey())
+
+	if err := c.ImportKey(name, publicKeyPEM, tags); err != nil {
+		return "", err
+	}
+
+	privateKeyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(private),
+	})
+	return string(privateKeyPEM), nil
+}
+
+// DeleteKey removes name's key-chunk tags from the resource group, along
+// with any bosh-classroom tags recorded alongside it.
+func (c *Client) DeleteKey(name string) error {
+	group, err := c.resourceGroup()
+	if err != nil {
+		return err
+	}
+	for i := 0; ; i++ {
+		tag := keyChunkTagFor(name, i)
+		if _, ok := group.Tags[tag]; !ok {
+			break
+		}
+		delete(group.Tags, tag)
+	}
+	prefix := classroomTagPrefix + name + ":"
+	for tag := range group.Tags {
+		if strings.HasPrefix(tag, prefix) {
+			delete(group.Tags, tag)
+		}
+	}
+	return c.updateResourceGroupTags(group)
+}
+
+// ImportKey records publicKeyPEM as resource group tags named after name,
+// without ever generating or seeing a private key.
+func (c *Client) ImportKey(name string, publicKeyPEM []byte, tags map[string]string) error {
+	group, err := c.resourceGroup()
+	if err != nil {
+		return err
+	}
+	if group.Tags == nil {
+		group.Tags = map[string]*string{}
+	}
+	value := strings.TrimSpace(string(publicKeyPEM))
+	for i, piece := range chunk(value, maxTagValueLength) {
+		piece := piece
+		group.Tags[keyChunkTagFor(name, i)] = &piece
+	}
+	for tag, tagValue := range tags {
+		tagValue := tagValue
+		group.Tags[classroomTagKeyFor(name, tag)] = &tagValue
+	}
+	return c.updateResourceGroupTags(group)
+}
+
+// KeyFingerprint returns the fingerprint of the public key currently
+// tagged under name, using the same algorithm AWS uses for imported keys
+// so fingerprints are comparable across providers.
+func (c *Client) KeyFingerprint(name string) (string, error) {
+	group, err := c.resourceGroup()
+	if err != nil {
+		return "", err
+	}
+	publicKeyPEM, ok := reassembleKey(group.Tags, name)
+	if !ok {
+		return "", fmt.Errorf("no key named '%s' is registered", name)
+	}
+	return iaas.FingerprintForImportedKey([]byte(publicKeyPEM))
+}
+
+// reassembleKey concatenates name's numbered key-chunk tags back into the
+// original public key, in order, stopping at the first missing index.
+func reassembleKey(tags map[string]*string, name string) (string, bool) {
+	var b strings.Builder
+	for i := 0; ; i++ {
+		value, ok := tags[keyChunkTagFor(name, i)]
+		if !ok || value == nil {
+			break
+		}
+		b.WriteString(*value)
+	}
+	if b.Len() == 0 {
+		return "", false
+	}
+	return b.String(), true
+}
+
+// ListKeys returns the names of all registered keys whose name starts
+// with prefix.
+func (c *Client) ListKeys(prefix string) ([]string, error) {
+	group, err := c.resourceGroup()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for tag := range group.Tags {
+		if !strings.HasPrefix(tag, keyTagPrefix) || !strings.HasSuffix(tag, "/0") {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(tag, keyTagPrefix), "/0")
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// Tags returns the bosh-classroom tags recorded alongside the key named
+// name.
+func (c *Client) Tags(name string) (map[string]string, error) {
+	group, err := c.resourceGroup()
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := classroomTagPrefix + name + ":"
+	tags := map[string]string{}
+	for tag, value := range group.Tags {
+		if value == nil || !strings.HasPrefix(tag, prefix) {
+			continue
+		}
+		tags[strings.TrimPrefix(tag, prefix)] = *value
+	}
+	return tags, nil
+}
+
+// ListClassroomsByTag returns the names of all classrooms whose tags
+// match all of filters.
+func (c *Client) ListClassroomsByTag(filters map[string]string) ([]string, error) {
+	names, err := c.ListKeys("")
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := c.resourceGroup()
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []string
+	for _, name := range names {
+		if !matchesAllTags(group.Tags, name, filters) {
+			continue
+		}
+		classroomName, ok := group.Tags[classroomTagKeyFor(name, iaas.TagPrefix+"name")]
+		if ok && classroomName != nil {
+			matched = append(matched, *classroomName)
+		}
+	}
+	return matched, nil
+}
+
+func matchesAllTags(tags map[string]*string, name string, filters map[string]string) bool {
+	for key, value := range filters {
+		tagValue, ok := tags[classroomTagKeyFor(name, iaas.TagPrefix+key)]
+		if !ok || tagValue == nil || *tagValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Client) resourceGroup() (resources.Group, error) {
+	return c.Groups.Get(context.Background(), c.ResourceGroup)
+}
+
+func (c *Client) updateResourceGroupTags(group resources.Group) error {
+	_, err := c.Groups.Update(context.Background(), c.ResourceGroup, resources.GroupPatchable{
+		Tags: group.Tags,
+	})
+	if err != nil {
+		return fmt.Errorf("could not update resource group tags: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package azure
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+)
+
+// StoreObject uploads data to c.Container under name.
+func (c *Client) StoreObject(name string, data []byte, downloadFileName, contentType string, tags map[string]string) error {
+	container := c.Blobs.GetContainerReference(c.Container)
+	blob := container.GetBlobReference(name)
+	blob.Properties.ContentType = contentType
+	blob.Properties.ContentDisposition = fmt.Sprintf("attachment; filename=%q", downloadFileName)
+	blob.Metadata = tags
+	return blob.CreateBlockBlobFromReader(bytes.NewReader(data), nil)
+}
+
+// FetchObject downloads and returns the bytes previously stored under name.
+func (c *Client) FetchObject(name string) ([]byte, error) {
+	container := c.Blobs.GetContainerReference(c.Container)
+	r, err := container.GetBlobReference(name).Get(nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch '%s': %s", name, err)
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+// DeleteObject removes name from c.Container.
+func (c *Client) DeleteObject(name string) error {
+	container := c.Blobs.GetContainerReference(c.Container)
+	_, err := container.GetBlobReference(name).DeleteIfExists(nil)
+	return err
+}
+
+// URLForObject returns the public Blob Storage URL for name.
+func (c *Client) URLForObject(name string) string {
+	return c.Blobs.GetContainerReference(c.Container).GetBlobReference(name).GetURL()
+}
@@ -0,0 +1,56 @@
+package azure
+
+import "encoding/json"
+
+// rawTemplate unmarshals an ARM template JSON string into the generic
+// shape the SDK expects for Deployment.Properties.Template.
+func rawTemplate(template string) interface{} {
+	var parsed map[string]interface{}
+	if err := json.Unmarshal([]byte(template), &parsed); err != nil {
+		// CreateStack surfaces malformed templates as a deployment error
+		// from the API instead, so an empty template is passed through
+		// here and the real error comes back from CreateOrUpdate.
+		parsed = map[string]interface{}{}
+	}
+	return parsed
+}
+
+// armParameters wraps flat classroom parameters in ARM's
+// {"name": {"value": ...}} parameter shape.
+func armParameters(parameters map[string]string) interface{} {
+	wrapped := map[string]interface{}{}
+	for name, value := range parameters {
+		wrapped[name] = map[string]interface{}{"value": value}
+	}
+	return wrapped
+}
+
+// armTags converts tags to ARM's map[string]*string deployment tag shape.
+func armTags(tags map[string]string) map[string]*string {
+	out := map[string]*string{}
+	for key, value := range tags {
+		value := value
+		out[key] = &value
+	}
+	return out
+}
+
+// extractARMParameters unwraps ARM's {"name": {"value": ...}} parameter
+// shape back into the flat map Controller works with.
+func extractARMParameters(parameters interface{}) map[string]string {
+	result := map[string]string{}
+	wrapped, ok := parameters.(map[string]interface{})
+	if !ok {
+		return result
+	}
+	for name, raw := range wrapped {
+		entry, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if value, ok := entry["value"].(string); ok {
+			result[name] = value
+		}
+	}
+	return result
+}
@@ -0,0 +1,283 @@
+package controller
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/daverc1-pivotal/bosh-classroom/proctor/iaas"
+)
+
+// manifestFile and checksumFile name the two entries a bundle's tar.gz
+// carries: the classroom's state, and a detached SHA-256 of it. There's no
+// key-management infrastructure anywhere in this repo to sign the manifest
+// for real, so the checksum sidecar is the honest stand-in: it catches
+// truncated or corrupted hand-offs, not tampering by a malicious operator.
+const (
+	manifestFile = "manifest.json"
+	checksumFile = "manifest.json.sha256"
+)
+
+// bundleManifest is everything ImportClassroom needs to recreate a
+// classroom's infrastructure stack and, if the keypair was provider-
+// generated rather than imported, its private key.
+type bundleManifest struct {
+	Provider   string            `json:"provider"`
+	Name       string            `json:"name"`
+	Template   string            `json:"template"`
+	Parameters map[string]string `json:"parameters"`
+	Hosts      map[string]string `json:"hosts"`
+
+	// PublicKeyPEM and PrivateKeyPEM are mutually exclusive: an imported-key
+	// classroom only ever has a public key to hand off, while a
+	// provider-generated one carries its private key too. ExportClassroom
+	// always sets exactly one of them; it has no option to leave the key
+	// material out of a bundle.
+	PublicKeyPEM  string `json:"public_key_pem,omitempty"`
+	PrivateKeyPEM string `json:"private_key_pem,omitempty"`
+}
+
+// ExportClassroom packages name's infrastructure template, resolved stack
+// parameters and hosts, and (if it was ever uploaded) its private key into
+// a tar+gzip bundle written to w, alongside a checksum of the manifest so a
+// truncated hand-off is caught on import rather than silently misapplied.
+func (c *Controller) ExportClassroom(name string, w io.Writer) error {
+	prefixedName := prefix(name)
+	provider := c.provider()
+
+	template, ok := c.Templates[provider]
+	if !ok {
+		return fmt.Errorf("no template configured for provider %s", provider)
+	}
+
+	_, stackID, parameters, err := c.IaaSClient.DescribeStack(prefixedName)
+	if err != nil {
+		return err
+	}
+	hosts, err := c.IaaSClient.GetHostsFromStackID(stackID)
+	if err != nil {
+		return fmt.Errorf("error fetching hosts for stack: %s", err)
+	}
+
+	tags, err := c.IaaSClient.Tags(prefixedName)
+	if err != nil {
+		return err
+	}
+	imported := tags[iaas.TagPrefix+"key-source"] == iaas.KeySourceImported
+
+	manifest := bundleManifest{
+		Provider:   provider,
+		Name:       name,
+		Template:   template,
+		Parameters: parameters,
+		Hosts:      hosts,
+	}
+
+	if imported {
+		// Export happens in its own CLI invocation, so ImportedKeyPaths is
+		// only populated here if this is the same process that ran
+		// CreateClassroom --public-key; the private half never left the
+		// instructor's laptop, so there's nowhere else to recover the
+		// public key from.
+		publicKeyPath, ok := c.ImportedKeyPaths[name]
+		if !ok {
+			return fmt.Errorf(
+				"'%s' was created with an imported key, but this process doesn't know its local path; "+
+					"re-run export from the process (or --public-key) that created it", name)
+		}
+		publicKeyPEMBytes, err := ioutil.ReadFile(publicKeyPath)
+		if err != nil {
+			return fmt.Errorf("could not read public key '%s': %s", publicKeyPath, err)
+		}
+		manifest.PublicKeyPEM = string(publicKeyPEMBytes)
+	} else {
+		privateKeyPEMBytes, err := c.IaaSClient.FetchObject("keys/" + prefixedName)
+		if err != nil {
+			return fmt.Errorf("could not fetch private key for '%s': %s", name, err)
+		}
+		manifest.PrivateKeyPEM = string(privateKeyPEMBytes)
+	}
+
+	return writeBundle(w, manifest)
+}
+
+func writeBundle(w io.Writer, manifest bundleManifest) error {
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode manifest: %s", err)
+	}
+	sum := sha256.Sum256(manifestBytes)
+	checksumBytes := []byte(hex.EncodeToString(sum[:]))
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	for _, entry := range []struct {
+		name string
+		data []byte
+	}{
+		{manifestFile, manifestBytes},
+		{checksumFile, checksumBytes},
+	} {
+		if err := tw.WriteHeader(&tar.Header{
+			Name: entry.name,
+			Mode: 0644,
+			Size: int64(len(entry.data)),
+		}); err != nil {
+			return fmt.Errorf("could not write bundle entry '%s': %s", entry.name, err)
+		}
+		if _, err := tw.Write(entry.data); err != nil {
+			return fmt.Errorf("could not write bundle entry '%s': %s", entry.name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("could not finalize bundle: %s", err)
+	}
+	return gz.Close()
+}
+
+// ImportClassroom recreates the classroom described by a bundle previously
+// written by ExportClassroom, re-registering its keypair and resubmitting
+// its infrastructure stack. If the bundle's AMI parameter is missing, or
+// present but no longer available in c.Region (a bundle exported in a
+// different region, say), it's re-resolved against c.Region the same way
+// CreateClassroom does, instead of being submitted as-is and failing
+// opaquely at stack-creation time.
+func (c *Controller) ImportClassroom(name string, r io.Reader) error {
+	manifest, err := readBundle(r)
+	if err != nil {
+		return err
+	}
+
+	prefixedName := prefix(name)
+	keySource := iaas.KeySourceGenerated
+	if manifest.PublicKeyPEM != "" {
+		keySource = iaas.KeySourceImported
+	}
+	tags := iaas.ClassroomTags(name, c.Owner, time.Now(), c.ttl(), keySource)
+
+	if err := c.resolveBundleImage(manifest); err != nil {
+		return err
+	}
+
+	if err := c.importBundleKey(prefixedName, manifest, tags); err != nil {
+		return err
+	}
+
+	c.Log.Println(0, "Recreating infrastructure stack %s", c.Log.Green("%s", prefixedName))
+	_, err = c.IaaSClient.CreateStack(prefixedName, manifest.Template, manifest.Parameters, tags)
+	return err
+}
+
+// resolveBundleImage ensures manifest.Parameters["AMI"] names an image
+// IaaSClient.ImageAvailable confirms exists in c.Region, re-resolving it
+// via AtlasClient when it's missing or stale. manifest.Parameters is a map,
+// so this mutates the same one the caller holds.
+func (c *Controller) resolveBundleImage(manifest bundleManifest) error {
+	image := manifest.Parameters["AMI"]
+	if image != "" {
+		available, err := c.IaaSClient.ImageAvailable(image)
+		if err != nil {
+			return err
+		}
+		if available {
+			return nil
+		}
+		c.Log.Println(0, "Bundle's image %s is not available in this region; looking up latest image for %s on %s",
+			c.Log.Green("%s", image), c.Log.Green("%s", c.VagrantBoxName), manifest.Provider)
+	} else {
+		c.Log.Println(0, "Bundle has no image recorded; looking up latest image for %s on %s",
+			c.Log.Green("%s", c.VagrantBoxName), manifest.Provider)
+	}
+
+	resolved, err := c.AtlasClient.GetLatestImages(c.VagrantBoxName, manifest.Provider, c.Region)
+	if err != nil {
+		return err
+	}
+	manifest.Parameters["AMI"] = resolved
+	return nil
+}
+
+// importBundleKey re-registers whichever half of the keypair the bundle
+// carries. A bundle with PublicKeyPEM came from an imported-key classroom,
+// so only the public key is re-imported, exactly as if the instructor ran
+// CreateClassroom with --public-key again; importBundleKey does not
+// repopulate Controller.ImportedKeyPaths, since that field's contract is a
+// local file path the caller controls, not bundle-sourced key material.
+func (c *Controller) importBundleKey(prefixedName string, manifest bundleManifest, tags map[string]string) error {
+	switch {
+	case manifest.PublicKeyPEM != "":
+		c.Log.Println(0, "Importing SSH Keypair %s from bundle", c.Log.Green("%s", prefixedName))
+		return c.IaaSClient.ImportKey(prefixedName, []byte(manifest.PublicKeyPEM), tags)
+
+	case manifest.PrivateKeyPEM != "":
+		c.Log.Println(0, "Re-registering SSH Keypair %s from bundle", c.Log.Green("%s", prefixedName))
+		publicKeyPEMBytes, err := iaas.PublicKeyFromPrivatePEM([]byte(manifest.PrivateKeyPEM))
+		if err != nil {
+			return err
+		}
+		if err := c.IaaSClient.ImportKey(prefixedName, publicKeyPEMBytes, tags); err != nil {
+			return err
+		}
+		s3Name := "keys/" + prefixedName
+		return c.IaaSClient.StoreObject(
+			s3Name, []byte(manifest.PrivateKeyPEM),
+			"bosh101_ssh_key.pem", "application/x-pem-file", tags)
+
+	default:
+		return fmt.Errorf("bundle for '%s' has no key material to import", manifest.Name)
+	}
+}
+
+func readBundle(r io.Reader) (bundleManifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return bundleManifest{}, fmt.Errorf("not a valid bundle: %s", err)
+	}
+	defer gz.Close()
+
+	var manifestBytes, checksumBytes []byte
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return bundleManifest{}, fmt.Errorf("not a valid bundle: %s", err)
+		}
+
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return bundleManifest{}, fmt.Errorf("could not read bundle entry '%s': %s", header.Name, err)
+		}
+		switch header.Name {
+		case manifestFile:
+			manifestBytes = data
+		case checksumFile:
+			checksumBytes = data
+		}
+	}
+
+	if manifestBytes == nil || checksumBytes == nil {
+		return bundleManifest{}, fmt.Errorf("bundle is missing %s or %s", manifestFile, checksumFile)
+	}
+
+	sum := sha256.Sum256(manifestBytes)
+	if hex.EncodeToString(sum[:]) != string(checksumBytes) {
+		return bundleManifest{}, fmt.Errorf("bundle checksum mismatch: %s does not match %s", manifestFile, checksumFile)
+	}
+
+	var manifest bundleManifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return bundleManifest{}, fmt.Errorf("could not decode manifest: %s", err)
+	}
+	return manifest, nil
+}
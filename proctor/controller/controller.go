@@ -1,29 +1,31 @@
 package controller
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"regexp"
 	"strconv"
-	"strings"
+	"time"
+
+	"github.com/daverc1-pivotal/bosh-classroom/proctor/controller/format"
+	"github.com/daverc1-pivotal/bosh-classroom/proctor/iaas"
 )
 
-type atlasClient interface {
-	GetLatestAMIs(string) (map[string]string, error)
-}
+// DefaultProvider is used when Controller.Provider isn't set, so existing
+// AWS-only callers keep working unchanged.
+const DefaultProvider = "aws"
 
-type awsClient interface {
-	CreateKey(name string) (string, error)
-	DeleteKey(name string) error
-	ListKeys(prefix string) ([]string, error)
-	StoreObject(name string, bytes []byte, downloadFileName, contentType string) error
-	DeleteObject(name string) error
-	URLForObject(name string) string
-	CreateStack(name string, template string, parameters map[string]string) (string, error)
-	DeleteStack(name string) error
-	DescribeStack(name string) (string, string, map[string]string, error)
-	GetHostsFromStackID(stackID string) (map[string]string, error)
+// DefaultTTL is used when Controller.TTL isn't set: long enough to cover a
+// full day's workshop, short enough that a forgotten classroom doesn't
+// linger for long after ReapExpired starts running.
+const DefaultTTL = 24 * time.Hour
+
+// atlasClient generalizes Vagrant Cloud's box-image lookup: a box has a
+// distinct image per IaaS provider, and the same box/provider pair can
+// have a different image per region.
+type atlasClient interface {
+	GetLatestImages(box, provider, region string) (string, error)
 }
 
 type cliLogger interface {
@@ -33,113 +35,240 @@ type cliLogger interface {
 
 type Controller struct {
 	AtlasClient atlasClient
-	AWSClient   awsClient
+	IaaSClient  iaas.Client
 	Log         cliLogger
 
+	// Provider selects which IaaS backend IaaSClient talks to (e.g. "aws",
+	// "gcp", "azure"), set from the --provider flag or BOSH_CLASSROOM_PROVIDER.
+	// Defaults to DefaultProvider when empty.
+	Provider string
+
 	VagrantBoxName string
 	Region         string
-	Template       string
+
+	// Owner identifies who's creating classrooms (e.g. an instructor's
+	// email), recorded on every resource's bosh-classroom:owner tag.
+	Owner string
+
+	// TTL bounds how long a classroom may run before ReapExpired considers
+	// it stale. Defaults to DefaultTTL when zero.
+	TTL time.Duration
+
+	// Templates holds one infrastructure template per provider (a
+	// CloudFormation template for "aws", a Deployment Manager config for
+	// "gcp", an ARM template for "azure"), keyed the same way as Provider.
+	Templates map[string]string
+
+	// ImportedKeyPaths records, per classroom name, the path to the local
+	// public key used to import that classroom's keypair instead of letting
+	// the provider generate one. A classroom with an entry here never had
+	// its private key uploaded to S3.
+	ImportedKeyPaths map[string]string
+}
+
+func (c *Controller) provider() string {
+	if c.Provider == "" {
+		return DefaultProvider
+	}
+	return c.Provider
+}
+
+func (c *Controller) ttl() time.Duration {
+	if c.TTL == 0 {
+		return DefaultTTL
+	}
+	return c.TTL
 }
 
 func prefix(classroomName string) string {
 	return "classroom-" + classroomName
 }
 
-func (c *Controller) CreateClassroom(name string, number int) error {
+// CreateClassroom stands up a new classroom with number instances on
+// c.Provider (or DefaultProvider if unset). If publicKeyPath is non-empty,
+// its contents are imported as the classroom's keypair and the matching
+// private key is assumed to already be held by the caller; nothing is
+// uploaded to S3. If publicKeyPath is empty, the provider generates the
+// keypair as before and the private key is uploaded to S3. If wait is
+// true, CreateClassroom blocks until the stack finishes creating (see
+// WaitForClassroom) instead of returning as soon as it's submitted.
+func (c *Controller) CreateClassroom(name string, number int, publicKeyPath string, wait bool) error {
 	const requiredPattern = `^[a-zA-Z][-a-zA-Z0-9]*$`
 	regex := regexp.MustCompile(requiredPattern)
 	if !regex.MatchString(name) {
 		return fmt.Errorf("invalid name: must match pattern %s", requiredPattern)
 	}
 
-	c.Log.Println(0, "Looking up latest AMI for %s", c.Log.Green("%s", c.VagrantBoxName))
-	amiMap, err := c.AtlasClient.GetLatestAMIs(c.VagrantBoxName)
-	if err != nil {
-		return err
-	}
-
-	ami, ok := amiMap[c.Region]
+	provider := c.provider()
+	template, ok := c.Templates[provider]
 	if !ok {
-		return fmt.Errorf("Couldn't find AMI in region %s", c.Region)
+		return fmt.Errorf("no template configured for provider %s", provider)
 	}
-	c.Log.Println(0, "Found %s", c.Log.Green("%s", ami))
 
-	prefixedName := prefix(name)
-	c.Log.Println(0, "Creating SSH Keypair %s", c.Log.Green("%s", prefixedName))
-	privateKeyPEMBytes, err := c.AWSClient.CreateKey(prefixedName)
+	c.Log.Println(0, "Looking up latest image for %s on %s", c.Log.Green("%s", c.VagrantBoxName), provider)
+	image, err := c.AtlasClient.GetLatestImages(c.VagrantBoxName, provider, c.Region)
 	if err != nil {
 		return err
 	}
+	c.Log.Println(0, "Found %s", c.Log.Green("%s", image))
 
-	s3Name := "keys/" + prefixedName
-	s3URL := c.AWSClient.URLForObject(s3Name)
-	c.Log.Println(0, "Uploading private key to %s", c.Log.Green("%s", s3URL))
-	err = c.AWSClient.StoreObject(
-		s3Name, []byte(privateKeyPEMBytes),
-		"bosh101_ssh_key.pem", "application/x-pem-file")
-	if err != nil {
-		return err
+	prefixedName := prefix(name)
+	keySource := iaas.KeySourceGenerated
+	if publicKeyPath != "" {
+		keySource = iaas.KeySourceImported
 	}
+	tags := iaas.ClassroomTags(name, c.Owner, time.Now(), c.ttl(), keySource)
 
-	c.Log.Println(0, "Creating CloudFormation stack %s", c.Log.Green("%s", prefixedName))
-	_, err = c.AWSClient.CreateStack(prefixedName, c.Template, map[string]string{
-		"AMI":           ami,
+	if publicKeyPath != "" {
+		c.Log.Println(0, "Importing SSH Keypair %s from %s", c.Log.Green("%s", prefixedName), publicKeyPath)
+		publicKeyPEMBytes, err := ioutil.ReadFile(publicKeyPath)
+		if err != nil {
+			return fmt.Errorf("could not read public key '%s': %s", publicKeyPath, err)
+		}
+		if err := c.IaaSClient.ImportKey(prefixedName, publicKeyPEMBytes, tags); err != nil {
+			return err
+		}
+		if c.ImportedKeyPaths == nil {
+			c.ImportedKeyPaths = map[string]string{}
+		}
+		c.ImportedKeyPaths[name] = publicKeyPath
+		c.Log.Println(0, "Private key stays on the instructor's laptop; skipping S3 upload")
+	} else {
+		c.Log.Println(0, "Creating SSH Keypair %s", c.Log.Green("%s", prefixedName))
+		privateKeyPEMBytes, err := c.IaaSClient.CreateKey(prefixedName, tags)
+		if err != nil {
+			return err
+		}
+
+		s3Name := "keys/" + prefixedName
+		s3URL := c.IaaSClient.URLForObject(s3Name)
+		c.Log.Println(0, "Uploading private key to %s", c.Log.Green("%s", s3URL))
+		err = c.IaaSClient.StoreObject(
+			s3Name, []byte(privateKeyPEMBytes),
+			"bosh101_ssh_key.pem", "application/x-pem-file", tags)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.Log.Println(0, "Creating infrastructure stack %s", c.Log.Green("%s", prefixedName))
+	_, err = c.IaaSClient.CreateStack(prefixedName, template, map[string]string{
+		"AMI":           image,
 		"KeyName":       prefixedName,
 		"InstanceCount": strconv.Itoa(number),
-	})
+	}, tags)
+	if err != nil {
+		return err
+	}
 
-	return err
+	if wait {
+		return c.WaitForClassroom(name, DefaultWaitTimeout)
+	}
+	return nil
 }
 
-func (c *Controller) DestroyClassroom(name string) error {
+// DestroyClassroom tears down a classroom's stack and keypair, and its
+// uploaded private key if it has one (a classroom created with
+// --public-key never had one to delete). If wait is true, it blocks until
+// the stack finishes deleting instead of returning as soon as deletion is
+// submitted.
+func (c *Controller) DestroyClassroom(name string, wait bool) error {
 	prefixedName := prefix(name)
 
-	c.Log.Println(0, "Deleting CloudFormation stack %s", c.Log.Green("%s", prefixedName))
-	err := c.AWSClient.DeleteStack(prefixedName)
+	tags, err := c.IaaSClient.Tags(prefixedName)
 	if err != nil {
 		return err
 	}
+	imported := tags[iaas.TagPrefix+"key-source"] == iaas.KeySourceImported
+
+	c.Log.Println(0, "Deleting infrastructure stack %s", c.Log.Green("%s", prefixedName))
+	if err := c.IaaSClient.DeleteStack(prefixedName); err != nil {
+		return err
+	}
+
+	if wait {
+		if err := c.WaitForClassroom(name, DefaultWaitTimeout); err != nil {
+			return err
+		}
+	}
 
 	c.Log.Println(0, "Deleting classroom keypair...")
-	err = c.AWSClient.DeleteKey(prefixedName)
-	if err != nil {
+	if err := c.IaaSClient.DeleteKey(prefixedName); err != nil {
 		return err
 	}
+	delete(c.ImportedKeyPaths, name)
+
+	if imported {
+		return nil
+	}
 
 	s3Name := "keys/" + prefixedName
 	c.Log.Println(0, "Deleting private key from S3...")
-	err = c.AWSClient.DeleteObject(s3Name)
-	return err
+	return c.IaaSClient.DeleteObject(s3Name)
 }
 
-func (c *Controller) ListClassrooms(format string) (string, error) {
-	keys, err := c.AWSClient.ListKeys("classroom-")
+// ReapExpired destroys every classroom whose bosh-classroom:created-at and
+// bosh-classroom:ttl tags put it past due, so a cron job can clean up
+// workshop environments nobody remembered to tear down. A classroom with
+// missing or malformed tags (created before this feature existed, say) is
+// left alone rather than guessed at.
+func (c *Controller) ReapExpired() error {
+	names, err := c.IaaSClient.ListClassroomsByTag(nil)
 	if err != nil {
-		return "", err
+		return err
 	}
-	for i, k := range keys {
-		keys[i] = strings.TrimPrefix(k, "classroom-")
+
+	for _, name := range names {
+		tags, err := c.IaaSClient.Tags(prefix(name))
+		if err != nil {
+			return err
+		}
+
+		createdAt, err := time.Parse(time.RFC3339, tags[iaas.TagPrefix+"created-at"])
+		if err != nil {
+			continue
+		}
+		ttl, err := time.ParseDuration(tags[iaas.TagPrefix+"ttl"])
+		if err != nil {
+			continue
+		}
+
+		if time.Now().After(createdAt.Add(ttl)) {
+			c.Log.Println(0, "Reaping expired classroom %s", c.Log.Green("%s", name))
+			if err := c.DestroyClassroom(name, false); err != nil {
+				return err
+			}
+		}
 	}
+	return nil
+}
 
-	if format == "json" {
-		jsonBytes, err := json.MarshalIndent(keys, "", "    ")
-		return string(jsonBytes), err
+func (c *Controller) ListClassrooms(outputFormat string) (string, error) {
+	keys, err := c.IaaSClient.ListClassroomsByTag(nil)
+	if err != nil {
+		return "", err
 	}
-	if format == "plain" {
-		return strings.Join(keys, "\n"), nil
+
+	formatter, err := format.New(outputFormat)
+	if err != nil {
+		return "", err
 	}
-	return "", fmt.Errorf("expected format to be either 'json' or 'plain'")
+	return formatter.Format(keys)
 }
 
-func (c *Controller) DescribeClassroom(name, format string) (string, error) {
+func (c *Controller) DescribeClassroom(name, outputFormat string) (string, error) {
 	prefixedName := prefix(name)
 
-	status, stackID, parameters, err := c.AWSClient.DescribeStack(prefixedName)
+	status, stackID, parameters, err := c.IaaSClient.DescribeStack(prefixedName)
 	if err != nil {
 		return "", err
 	}
 
-	keyURL := c.AWSClient.URLForObject("keys/" + prefixedName)
+	tags, err := c.IaaSClient.Tags(prefixedName)
+	if err != nil {
+		return "", err
+	}
+	imported := tags[iaas.TagPrefix+"key-source"] == iaas.KeySourceImported
 
 	var description struct {
 		Status string            `json:"status"`
@@ -148,31 +277,48 @@ func (c *Controller) DescribeClassroom(name, format string) (string, error) {
 		Hosts  map[string]string `json:"hosts"`
 	}
 	description.Status = status
-	description.SSHKey = keyURL
+
+	if imported {
+		description.SSHKey = "(imported; private key never left the instructor's laptop)"
+		// The registered-vs-local fingerprint cross-check only runs when
+		// this process happens to know the local public key path (i.e. the
+		// process that ran CreateClassroom --public-key); any other
+		// process still reports the classroom correctly as imported, it
+		// just can't re-verify the fingerprint.
+		if publicKeyPath, ok := c.ImportedKeyPaths[name]; ok {
+			publicKeyPEMBytes, err := ioutil.ReadFile(publicKeyPath)
+			if err != nil {
+				return "", fmt.Errorf("could not read public key '%s': %s", publicKeyPath, err)
+			}
+			localFingerprint, err := iaas.FingerprintForImportedKey(publicKeyPEMBytes)
+			if err != nil {
+				return "", err
+			}
+			registeredFingerprint, err := c.IaaSClient.KeyFingerprint(prefixedName)
+			if err != nil {
+				return "", err
+			}
+			if localFingerprint != registeredFingerprint {
+				return "", fmt.Errorf(
+					"local public key '%s' does not match the keypair %s has registered for '%s'",
+					publicKeyPath, c.provider(), name)
+			}
+		}
+	} else {
+		description.SSHKey = c.IaaSClient.URLForObject("keys/" + prefixedName)
+	}
 	description.Number, err = strconv.Atoi(parameters["InstanceCount"])
 	if err != nil {
-		return "", errors.New("malformed CloudFormation stack: missing or invalid parameter 'InstanceCount'")
+		return "", errors.New("malformed infrastructure stack: missing or invalid parameter 'InstanceCount'")
 	}
-	description.Hosts, err = c.AWSClient.GetHostsFromStackID(stackID)
+	description.Hosts, err = c.IaaSClient.GetHostsFromStackID(stackID)
 	if err != nil {
 		return "", fmt.Errorf("error fetching hosts for stack: %s", err)
 	}
 
-	if format == "json" {
-		descriptionBytes, err := json.MarshalIndent(description, "", "    ")
-		return string(descriptionBytes), err
+	formatter, err := format.New(outputFormat)
+	if err != nil {
+		return "", err
 	}
-	if format == "plain" {
-		hosts := []string{}
-		for k, v := range description.Hosts {
-			hosts = append(hosts, fmt.Sprintf("%s\t%s", k, v))
-		}
-		return fmt.Sprintf("%s: %s\n%s: %d\n%s: %s\n%s:\n%s",
-			"status", description.Status,
-			"number", description.Number,
-			"ssh_key", description.SSHKey,
-			"hosts", strings.Join(hosts, "\n"),
-		), nil
-	}
-	return "", fmt.Errorf("expected format to be either 'json' or 'plain'")
+	return formatter.Format(description)
 }
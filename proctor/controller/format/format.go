@@ -0,0 +1,66 @@
+// Package format renders the values Controller's ListClassrooms and
+// DescribeClassroom produce in whichever output shape the caller asked
+// for, so both commands (and anything added later) share one
+// implementation instead of each hand-rolling its own json/plain
+// special-casing.
+package format
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Formatter renders value as a string. value is always something
+// encoding/json can marshal (a struct with json tags, a map, a slice of
+// strings, and so on); formatters that need a generic shape to walk
+// (table, tsv, jsonpath, go-template) round-trip it through JSON first via
+// normalize, so they don't need to know the concrete Go type a caller
+// passed in. plain is the exception: it reflects on the concrete value
+// directly so it can preserve struct field declaration order, matching
+// the hand-rolled plain output this package replaced.
+type Formatter interface {
+	Format(value interface{}) (string, error)
+}
+
+// New builds the Formatter named by format. format is one of "json",
+// "yaml", "plain", "table", "tsv", or a parameterized "jsonpath=<expr>"
+// / "go-template=<tmpl>".
+func New(format string) (Formatter, error) {
+	switch {
+	case format == "json":
+		return jsonFormatter{}, nil
+	case format == "yaml":
+		return yamlFormatter{}, nil
+	case format == "plain":
+		return plainFormatter{}, nil
+	case format == "table":
+		return tableFormatter{}, nil
+	case format == "tsv":
+		return tsvFormatter{}, nil
+	case strings.HasPrefix(format, "jsonpath="):
+		return jsonPathFormatter{expr: strings.TrimPrefix(format, "jsonpath=")}, nil
+	case strings.HasPrefix(format, "go-template="):
+		return newGoTemplateFormatter(strings.TrimPrefix(format, "go-template="))
+	default:
+		return nil, fmt.Errorf(
+			"unknown format '%s': expected json, yaml, plain, table, tsv, jsonpath=<expr>, or go-template=<tmpl>",
+			format)
+	}
+}
+
+// normalize round-trips value through JSON so every formatter that needs
+// to walk an arbitrary shape (rather than just re-marshal it) works off
+// the same map[string]interface{}/[]interface{}/scalar representation,
+// regardless of the concrete Go type the caller passed in.
+func normalize(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode value: %s", err)
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, fmt.Errorf("could not decode value: %s", err)
+	}
+	return generic, nil
+}
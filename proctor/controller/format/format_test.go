@@ -0,0 +1,139 @@
+package format
+
+import (
+	"strings"
+	"testing"
+)
+
+type sampleDescription struct {
+	Status string            `json:"status"`
+	Number int               `json:"number"`
+	SSHKey string            `json:"ssh_key"`
+	Hosts  map[string]string `json:"hosts"`
+}
+
+func sample() sampleDescription {
+	return sampleDescription{
+		Status: "CREATE_COMPLETE",
+		Number: 2,
+		SSHKey: "https://bucket.s3.amazonaws.com/keys/classroom-foo",
+		Hosts:  map[string]string{"host-2": "2.2.2.2", "host-1": "1.1.1.1"},
+	}
+}
+
+func TestNewRejectsUnknownFormat(t *testing.T) {
+	if _, err := New("xml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestNewAcceptsEveryDocumentedFormat(t *testing.T) {
+	for _, name := range []string{
+		"json", "yaml", "plain", "table", "tsv",
+		"jsonpath=.status", "go-template={{.status}}",
+	} {
+		if _, err := New(name); err != nil {
+			t.Errorf("New(%q): unexpected error: %s", name, err)
+		}
+	}
+}
+
+func TestPlainFormatMatchesTheOriginalLayout(t *testing.T) {
+	f, _ := New("plain")
+	out, err := f.Format(sample())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := strings.Join([]string{
+		"status: CREATE_COMPLETE",
+		"number: 2",
+		"ssh_key: https://bucket.s3.amazonaws.com/keys/classroom-foo",
+		"hosts:",
+		"host-1\t1.1.1.1",
+		"host-2\t2.2.2.2",
+	}, "\n")
+	if out != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, out)
+	}
+}
+
+func TestPlainFormatList(t *testing.T) {
+	f, _ := New("plain")
+	out, err := f.Format([]string{"foo", "bar"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if out != "foo\nbar" {
+		t.Errorf("expected %q, got %q", "foo\nbar", out)
+	}
+}
+
+func TestJSONPathFormat(t *testing.T) {
+	f, _ := New("jsonpath={.hosts.host-1}")
+	out, err := f.Format(sample())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if out != "1.1.1.1" {
+		t.Errorf("expected %q, got %q", "1.1.1.1", out)
+	}
+}
+
+func TestGoTemplateFormat(t *testing.T) {
+	f, err := New("go-template={{.status}} ({{.number}})")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	out, err := f.Format(sample())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if out != "CREATE_COMPLETE (2)" {
+		t.Errorf("expected %q, got %q", "CREATE_COMPLETE (2)", out)
+	}
+}
+
+func TestTableAndTSVAreDeterministicAndSorted(t *testing.T) {
+	for _, name := range []string{"table", "tsv"} {
+		f, _ := New(name)
+		first, err := f.Format(sample())
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", name, err)
+		}
+		for i := 0; i < 5; i++ {
+			again, err := f.Format(sample())
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %s", name, err)
+			}
+			if again != first {
+				t.Errorf("%s: output was not deterministic across repeated calls", name)
+			}
+		}
+		if !strings.Contains(first, "hosts.host-1") || !strings.Contains(first, "hosts.host-2") {
+			t.Errorf("%s: expected flattened hosts.host-1/hosts.host-2 rows, got:\n%s", name, first)
+		}
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	f, _ := New("json")
+	out, err := f.Format(sample())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(out, `"status": "CREATE_COMPLETE"`) {
+		t.Errorf("expected json output to contain the status field, got:\n%s", out)
+	}
+}
+
+func TestYAMLFormat(t *testing.T) {
+	f, _ := New("yaml")
+	out, err := f.Format(sample())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(out, "status: CREATE_COMPLETE") {
+		t.Errorf("expected yaml output to contain the status field, got:\n%s", out)
+	}
+}
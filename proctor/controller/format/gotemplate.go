@@ -0,0 +1,34 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// goTemplateFormatter executes a caller-supplied Go template against the
+// normalized value, the same way kubectl's "-o go-template=" does.
+type goTemplateFormatter struct {
+	tmpl *template.Template
+}
+
+func newGoTemplateFormatter(tmpl string) (goTemplateFormatter, error) {
+	parsed, err := template.New("format").Parse(tmpl)
+	if err != nil {
+		return goTemplateFormatter{}, fmt.Errorf("invalid go-template: %s", err)
+	}
+	return goTemplateFormatter{tmpl: parsed}, nil
+}
+
+func (f goTemplateFormatter) Format(value interface{}) (string, error) {
+	generic, err := normalize(value)
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	if err := f.tmpl.Execute(&out, generic); err != nil {
+		return "", fmt.Errorf("could not execute go-template: %s", err)
+	}
+	return out.String(), nil
+}
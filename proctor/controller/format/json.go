@@ -0,0 +1,13 @@
+package format
+
+import "encoding/json"
+
+type jsonFormatter struct{}
+
+func (jsonFormatter) Format(value interface{}) (string, error) {
+	out, err := json.MarshalIndent(value, "", "    ")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
@@ -0,0 +1,92 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPathFormatter supports a small, dot-path subset of kubectl's
+// JSONPath: "{.field.nested}" or "{.field[0].nested}", with the braces
+// optional. That's enough to pull a single value out of the
+// ListClassrooms/DescribeClassroom shapes without pulling in a full
+// JSONPath evaluator for a handful of fields.
+type jsonPathFormatter struct {
+	expr string
+}
+
+func (f jsonPathFormatter) Format(value interface{}) (string, error) {
+	generic, err := normalize(value)
+	if err != nil {
+		return "", err
+	}
+
+	expr := strings.TrimSpace(f.expr)
+	expr = strings.TrimPrefix(expr, "{")
+	expr = strings.TrimSuffix(expr, "}")
+	expr = strings.TrimPrefix(expr, ".")
+
+	result := generic
+	for _, segment := range splitPath(expr) {
+		result, err = step(result, segment)
+		if err != nil {
+			return "", fmt.Errorf("jsonpath '%s': %s", f.expr, err)
+		}
+	}
+	return scalar(result), nil
+}
+
+// splitPath breaks "field[0].nested" into ["field", "[0]", "nested"].
+func splitPath(expr string) []string {
+	var segments []string
+	var current strings.Builder
+	for _, r := range expr {
+		switch r {
+		case '.':
+			if current.Len() > 0 {
+				segments = append(segments, current.String())
+				current.Reset()
+			}
+		case '[':
+			if current.Len() > 0 {
+				segments = append(segments, current.String())
+				current.Reset()
+			}
+			current.WriteRune(r)
+		case ']':
+			current.WriteRune(r)
+			segments = append(segments, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		segments = append(segments, current.String())
+	}
+	return segments
+}
+
+func step(value interface{}, segment string) (interface{}, error) {
+	if strings.HasPrefix(segment, "[") && strings.HasSuffix(segment, "]") {
+		index, err := strconv.Atoi(strings.Trim(segment, "[]"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid index '%s'", segment)
+		}
+		list, ok := value.([]interface{})
+		if !ok || index < 0 || index >= len(list) {
+			return nil, fmt.Errorf("no element at index %d", index)
+		}
+		return list[index], nil
+	}
+
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("cannot index field '%s' into a non-object value", segment)
+	}
+	result, ok := m[segment]
+	if !ok {
+		return nil, fmt.Errorf("no field '%s'", segment)
+	}
+	return result, nil
+}
@@ -0,0 +1,78 @@
+package format
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// plainFormatter reproduces the original hand-rolled plain output exactly:
+// bare lines for a []string (as ListClassrooms returns), and "field:
+// value" lines in struct declaration order for everything else (as
+// DescribeClassroom returns), with any map[string]string field rendered as
+// its own "field:" header followed by tab-separated "key\tvalue" lines.
+// Unlike table/tsv/jsonpath, it doesn't go through normalize/rows: those
+// round-trip through JSON and flatten+sort every field alphabetically,
+// which would reorder DescribeClassroom's status/number/ssh_key/hosts
+// fields and rename "hosts" to dotted "hosts.<name>" rows — a visible
+// break for anything already scripting against --format plain.
+type plainFormatter struct{}
+
+func (plainFormatter) Format(value interface{}) (string, error) {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String {
+		lines := make([]string, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			lines[i] = v.Index(i).String()
+		}
+		return strings.Join(lines, "\n"), nil
+	}
+
+	if v.Kind() != reflect.Struct {
+		return "", fmt.Errorf("plain format only supports a struct or a list of strings, got %T", value)
+	}
+
+	var lines []string
+	for i := 0; i < v.NumField(); i++ {
+		field := fieldName(v.Type().Field(i))
+		fieldValue := v.Field(i).Interface()
+
+		if m, ok := fieldValue.(map[string]string); ok {
+			lines = append(lines, fmt.Sprintf("%s:\n%s", field, sortedPairs(m)))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: %v", field, fieldValue))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// fieldName returns a struct field's JSON tag name, falling back to its Go
+// name if untagged.
+func fieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// sortedPairs renders m as "key\tvalue" lines, one per line, sorted by key
+// since Go's map iteration order is otherwise random.
+func sortedPairs(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, len(keys))
+	for i, k := range keys {
+		lines[i] = fmt.Sprintf("%s\t%s", k, m[k])
+	}
+	return strings.Join(lines, "\n")
+}
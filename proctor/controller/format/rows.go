@@ -0,0 +1,75 @@
+package format
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// row is one line of tabular output: a field name and its rendered value.
+// table and tsv both build the same rows and differ only in how they lay
+// them out; plain does not use rows (see plainFormatter).
+type row struct {
+	Field string
+	Value string
+}
+
+// rows flattens value (already normalized to map[string]interface{},
+// []interface{}, or a scalar) into a sorted, deterministic list of rows.
+// A top-level list (as ListClassrooms returns) becomes one row per item
+// with no field name. A top-level map (as DescribeClassroom returns)
+// becomes one row per key, sorted; a nested map value (such as the hosts
+// map, whose Go-side iteration order is otherwise random) is flattened
+// into "key.subkey" rows, also sorted, which is what fixes the
+// nondeterministic host ordering the hand-rolled fmt.Sprintf describe
+// output used to have.
+func rows(value interface{}) []row {
+	switch v := value.(type) {
+	case []interface{}:
+		out := make([]row, len(v))
+		for i, item := range v {
+			out[i] = row{Value: scalar(item)}
+		}
+		return out
+	case map[string]interface{}:
+		return mapRows("", v)
+	default:
+		return []row{{Value: scalar(v)}}
+	}
+}
+
+func mapRows(prefix string, m map[string]interface{}) []row {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var out []row
+	for _, key := range keys {
+		field := key
+		if prefix != "" {
+			field = prefix + "." + key
+		}
+		if nested, ok := m[key].(map[string]interface{}); ok {
+			out = append(out, mapRows(field, nested)...)
+			continue
+		}
+		out = append(out, row{Field: field, Value: scalar(m[key])})
+	}
+	return out
+}
+
+func scalar(value interface{}) string {
+	if list, ok := value.([]interface{}); ok {
+		parts := make([]string, len(list))
+		for i, item := range list {
+			parts[i] = scalar(item)
+		}
+		return strings.Join(parts, ", ")
+	}
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
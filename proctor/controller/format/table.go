@@ -0,0 +1,37 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// tableFormatter renders rows as aligned columns with a header, the way
+// kubectl's "-o wide" table output does.
+type tableFormatter struct{}
+
+func (tableFormatter) Format(value interface{}) (string, error) {
+	generic, err := normalize(value)
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	w := tabwriter.NewWriter(&out, 0, 4, 2, ' ', 0)
+	if _, isList := generic.([]interface{}); isList {
+		fmt.Fprintln(w, "NAME")
+	} else {
+		fmt.Fprintln(w, "FIELD\tVALUE")
+	}
+	for _, r := range rows(generic) {
+		if r.Field == "" {
+			fmt.Fprintln(w, r.Value)
+		} else {
+			fmt.Fprintln(w, r.Field+"\t"+r.Value)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(out.String(), "\n"), nil
+}
@@ -0,0 +1,29 @@
+package format
+
+import "strings"
+
+// tsvFormatter renders rows as tab-separated values with a header line,
+// unaligned (unlike table), for piping into other tools.
+type tsvFormatter struct{}
+
+func (tsvFormatter) Format(value interface{}) (string, error) {
+	generic, err := normalize(value)
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	if _, isList := generic.([]interface{}); isList {
+		lines = append(lines, "NAME")
+	} else {
+		lines = append(lines, "FIELD\tVALUE")
+	}
+	for _, r := range rows(generic) {
+		if r.Field == "" {
+			lines = append(lines, r.Value)
+		} else {
+			lines = append(lines, r.Field+"\t"+r.Value)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
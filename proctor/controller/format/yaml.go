@@ -0,0 +1,17 @@
+package format
+
+import "gopkg.in/yaml.v3"
+
+type yamlFormatter struct{}
+
+func (yamlFormatter) Format(value interface{}) (string, error) {
+	generic, err := normalize(value)
+	if err != nil {
+		return "", err
+	}
+	out, err := yaml.Marshal(generic)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
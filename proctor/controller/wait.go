@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/daverc1-pivotal/bosh-classroom/proctor/iaas"
+)
+
+// DefaultWaitTimeout is how long WaitForClassroom waits for a stack to
+// reach a terminal status before giving up.
+const DefaultWaitTimeout = 30 * time.Minute
+
+const stackEventPollInterval = 5 * time.Second
+
+// terminalStackStatuses lists the terminal statuses WaitForClassroom
+// reports back as success. CREATE_FAILED, ROLLBACK_COMPLETE,
+// ROLLBACK_FAILED, and DELETE_FAILED are terminal too, but are handled as
+// explicit failures below rather than through this map, since they
+// shouldn't be reported back to the caller as success.
+var terminalStackStatuses = map[string]bool{
+	"CREATE_COMPLETE": true,
+	"DELETE_COMPLETE": true,
+	"UPDATE_COMPLETE": true,
+}
+
+// WaitForClassroom polls the classroom's infrastructure stack, printing
+// each stack event as it's observed, until the stack reaches a terminal
+// status or timeout elapses. On CREATE_FAILED or ROLLBACK_COMPLETE it
+// reports the failing resource's reason and cleans up the keypair and S3
+// object so the classroom name is free for a retry. On DELETE_FAILED it
+// reports the failing resource's reason and returns an error without
+// touching the keypair or uploaded key, since the stack (and whatever it
+// provisioned) may still be up. ROLLBACK_FAILED is handled the same way as
+// DELETE_FAILED: an error, with the keypair and uploaded key left in place.
+func (c *Controller) WaitForClassroom(name string, timeout time.Duration) error {
+	prefixedName := prefix(name)
+	deadline := time.Now().Add(timeout)
+	var since time.Time
+
+	for {
+		events, newest, err := c.IaaSClient.StackEventsSince(prefixedName, since)
+		if err != nil {
+			return err
+		}
+		since = newest
+		for _, e := range events {
+			line := fmt.Sprintf("%s: %s", e.LogicalResourceID, e.ResourceStatus)
+			if e.StatusReason != "" {
+				line += " (" + e.StatusReason + ")"
+			}
+			c.Log.Println(1, "%s", line)
+		}
+
+		status, _, _, err := c.IaaSClient.DescribeStack(prefixedName)
+		if err != nil {
+			return err
+		}
+
+		if status == "CREATE_FAILED" || status == "ROLLBACK_COMPLETE" {
+			c.logFailure(events)
+			return c.cleanupFailedCreate(name)
+		}
+		if status == "DELETE_FAILED" {
+			c.logFailure(events)
+			return fmt.Errorf(
+				"classroom '%s' failed to delete; its stack may still be up, so its keypair and uploaded key were left in place",
+				name)
+		}
+		if status == "ROLLBACK_FAILED" {
+			c.logFailure(events)
+			return fmt.Errorf(
+				"classroom '%s' failed to create and its rollback failed; its stack may still be up, so its keypair and uploaded key were left in place",
+				name)
+		}
+		if terminalStackStatuses[status] {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf(
+				"timed out after %s waiting for classroom '%s' to reach a terminal state (last status: %s)",
+				timeout, name, status)
+		}
+
+		time.Sleep(stackEventPollInterval)
+	}
+}
+
+// logFailure prints the last failed resource event, if any, so an operator
+// can see why a CREATE_FAILED, ROLLBACK_COMPLETE, or DELETE_FAILED stack
+// stopped where it did.
+func (c *Controller) logFailure(events []iaas.StackEvent) {
+	if failure := lastFailedEvent(events); failure != nil {
+		c.Log.Println(0, "Resource %s failed: %s",
+			c.Log.Green("%s", failure.LogicalResourceID), failure.StatusReason)
+	}
+}
+
+func lastFailedEvent(events []iaas.StackEvent) *iaas.StackEvent {
+	for i := len(events) - 1; i >= 0; i-- {
+		if strings.HasSuffix(events[i].ResourceStatus, "_FAILED") {
+			return &events[i]
+		}
+	}
+	return nil
+}
+
+// cleanupFailedCreate removes the keypair and S3 object left behind by a
+// classroom whose stack failed to create, so the name can be reused.
+func (c *Controller) cleanupFailedCreate(name string) error {
+	prefixedName := prefix(name)
+	c.Log.Println(0, "Cleaning up '%s' so it can be retried...", name)
+
+	tags, err := c.IaaSClient.Tags(prefixedName)
+	if err != nil {
+		return err
+	}
+	imported := tags[iaas.TagPrefix+"key-source"] == iaas.KeySourceImported
+
+	if err := c.IaaSClient.DeleteKey(prefixedName); err != nil {
+		return err
+	}
+	delete(c.ImportedKeyPaths, name)
+
+	if !imported {
+		if err := c.IaaSClient.DeleteObject("keys/" + prefixedName); err != nil {
+			return err
+		}
+	}
+
+	return fmt.Errorf("classroom '%s' failed to create; keypair and uploaded key removed, ready for retry", name)
+}
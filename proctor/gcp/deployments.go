@@ -0,0 +1,156 @@
+package gcp
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/api/deploymentmanager/v2"
+
+	"github.com/daverc1-pivotal/bosh-classroom/proctor/iaas"
+)
+
+// CreateStack creates a Deployment Manager deployment named name from
+// template, substituting parameters into the config before submitting it.
+// It returns name itself as the stack ID: Resources.List (used by
+// GetHostsFromStackID) and every other per-deployment lookup in this
+// package is keyed by the deployment's name, not its numeric Id, so
+// returning the numeric Id here would just have to be translated back.
+func (c *Client) CreateStack(name, template string, parameters, tags map[string]string) (string, error) {
+	config := substituteParameters(template, parameters)
+
+	deployment := &deploymentmanager.Deployment{
+		Name: name,
+		Target: &deploymentmanager.TargetConfiguration{
+			Config: &deploymentmanager.ConfigFile{Content: config},
+		},
+		Labels: dmLabels(tags),
+	}
+
+	if _, err := c.DM.Deployments.Insert(c.Project, deployment).Do(); err != nil {
+		return "", fmt.Errorf("could not create deployment '%s': %s", name, err)
+	}
+	return name, nil
+}
+
+// DeleteStack deletes the deployment named name.
+func (c *Client) DeleteStack(name string) error {
+	_, err := c.DM.Deployments.Delete(c.Project, name).Do()
+	return err
+}
+
+// DescribeStack returns name's deployment status, ID, and the parameters
+// it was last created or updated with. The deployment's name doubles as
+// its ID, the same as CreateStack returns, since that's what every other
+// per-deployment lookup in this package (Resources.List included) is
+// keyed by.
+func (c *Client) DescribeStack(name string) (string, string, map[string]string, error) {
+	deployment, err := c.DM.Deployments.Get(c.Project, name).Do()
+	if err != nil {
+		return "", "", nil, fmt.Errorf("could not describe deployment '%s': %s", name, err)
+	}
+
+	status := deploymentManagerStatus(deployment)
+
+	parameters := map[string]string{}
+	if deployment.Target != nil && deployment.Target.Config != nil {
+		parameters = extractParameters(deployment.Target.Config.Content)
+	}
+
+	return status, name, parameters, nil
+}
+
+// GetHostsFromStackID returns the instance name to public IP mapping for
+// the managed instance group created by stackID's deployment.
+func (c *Client) GetHostsFromStackID(stackID string) (map[string]string, error) {
+	resources, err := c.DM.Resources.List(c.Project, stackID).Do()
+	if err != nil {
+		return nil, fmt.Errorf("could not list resources for deployment '%s': %s", stackID, err)
+	}
+
+	hosts := map[string]string{}
+	for _, resource := range resources.Resources {
+		if resource.Type != "compute.v1.instance" {
+			continue
+		}
+		instance, err := c.Compute.Instances.Get(c.Project, c.Zone, resource.Name).Do()
+		if err != nil {
+			return nil, fmt.Errorf("could not describe instance '%s': %s", resource.Name, err)
+		}
+		for _, iface := range instance.NetworkInterfaces {
+			for _, access := range iface.AccessConfigs {
+				if access.NatIP != "" {
+					hosts[resource.Name] = access.NatIP
+				}
+			}
+		}
+	}
+	return hosts, nil
+}
+
+// StackEventsSince returns name's deployment operation history that
+// occurred strictly after since, generalized into the same
+// iaas.StackEvent shape used for CloudFormation events.
+func (c *Client) StackEventsSince(name string, since time.Time) ([]iaas.StackEvent, time.Time, error) {
+	ops, err := c.DM.Operations.List(c.Project).Filter(fmt.Sprintf("targetId eq %s", name)).Do()
+	if err != nil {
+		return nil, since, fmt.Errorf("could not list operations for deployment '%s': %s", name, err)
+	}
+
+	var events []iaas.StackEvent
+	newest := since
+	for _, op := range ops.Operations {
+		ts, err := time.Parse(time.RFC3339, op.InsertTime)
+		if err != nil || !ts.After(since) {
+			continue
+		}
+		events = append(events, iaas.StackEvent{
+			Timestamp:         ts,
+			LogicalResourceID: fmt.Sprintf("%d", op.TargetId),
+			ResourceType:      op.OperationType,
+			ResourceStatus:    op.Status,
+			StatusReason:      operationErrorMessage(op),
+		})
+		if ts.After(newest) {
+			newest = ts
+		}
+	}
+
+	return events, newest, nil
+}
+
+func operationErrorMessage(op *deploymentmanager.Operation) string {
+	if op.Error == nil || len(op.Error.Errors) == 0 {
+		return ""
+	}
+	return op.Error.Errors[0].Message
+}
+
+// dmLabels converts tags to Deployment Manager's label shape, replacing
+// ':' with '-' since DM label keys must match `[a-z]([-a-z0-9]*[a-z0-9])?`
+// and our tag keys are namespaced as "bosh-classroom:name".
+func dmLabels(tags map[string]string) []*deploymentmanager.DeploymentLabelEntry {
+	var labels []*deploymentmanager.DeploymentLabelEntry
+	for key, value := range tags {
+		labels = append(labels, &deploymentmanager.DeploymentLabelEntry{
+			Key:   strings.ReplaceAll(key, ":", "-"),
+			Value: value,
+		})
+	}
+	return labels
+}
+
+func deploymentManagerStatus(deployment *deploymentmanager.Deployment) string {
+	if deployment.Operation == nil {
+		return "CREATE_COMPLETE"
+	}
+	switch deployment.Operation.Status {
+	case "DONE":
+		if deployment.Operation.Error != nil {
+			return "CREATE_FAILED"
+		}
+		return "CREATE_COMPLETE"
+	default:
+		return "CREATE_IN_PROGRESS"
+	}
+}
@@ -0,0 +1,25 @@
+// Package gcp implements the iaas.Client contract on top of Google Cloud:
+// Deployment Manager for the infrastructure stack, project metadata for
+// keypairs, and Cloud Storage for uploaded artifacts.
+package gcp
+
+import (
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/deploymentmanager/v2"
+)
+
+// Client talks to a single GCP project on behalf of Controller.
+type Client struct {
+	Compute *compute.Service
+	DM      *deploymentmanager.Service
+	Storage *storage.Client
+
+	Project string
+	Bucket  string
+
+	// Zone is the zone instances created by CreateStack's deployment come
+	// up in (e.g. "us-central1-a"); Compute's instance-level API, unlike
+	// Deployment Manager's, is zone-scoped and has no "any zone" lookup.
+	Zone string
+}
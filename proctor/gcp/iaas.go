@@ -0,0 +1,5 @@
+package gcp
+
+import "github.com/daverc1-pivotal/bosh-classroom/proctor/iaas"
+
+var _ iaas.Client = (*Client)(nil)
@@ -0,0 +1,21 @@
+package gcp
+
+import (
+	"fmt"
+	"net/http"
+
+	"google.golang.org/api/googleapi"
+)
+
+// ImageAvailable reports whether image is a currently-registered Compute
+// image in this Client's project.
+func (c *Client) ImageAvailable(image string) (bool, error) {
+	_, err := c.Compute.Images.Get(c.Project, image).Do()
+	if err != nil {
+		if gerr, ok := err.(*googleapi.Error); ok && gerr.Code == http.StatusNotFound {
+			return false, nil
+		}
+		return false, fmt.Errorf("could not check image '%s': %s", image, err)
+	}
+	return true, nil
+}
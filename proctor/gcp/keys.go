@@ -0,0 +1,226 @@
+package gcp
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"google.golang.org/api/compute/v1"
+
+	"github.com/daverc1-pivotal/bosh-classroom/proctor/iaas"
+)
+
+const metadataKeyPrefix = "classroom-ssh-key-"
+const metadataTagsPrefix = "classroom-tags-"
+
+func metadataKeyFor(name string) string {
+	return metadataKeyPrefix + name
+}
+
+// metadataTagsKeyFor builds the project metadata key a classroom's tags are
+// stored under, as a single JSON-encoded value. GCP project metadata has
+// no native tagging concept, so tags are stored the same way keys are: as
+// a metadata item alongside the public key.
+func metadataTagsKeyFor(name string) string {
+	return metadataTagsPrefix + name
+}
+
+// CreateKey generates an RSA keypair locally (GCP has no equivalent of
+// EC2's CreateKeyPair) and publishes the public half as a project metadata
+// item, returning the PEM-encoded private key.
+func (c *Client) CreateKey(name string, tags map[string]string) (string, error) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return "", fmt.Errorf("could not generate keypair: %s", err)
+	}
+
+	signer, err := ssh.NewSignerFromKey(private)
+	if err != nil {
+		return "", fmt.Errorf("could not derive public key: %s", err)
+	}
+	publicKeyPEM := ssh.MarshalAuthorizedKey(signer.PublicKey())
+
+	if err := c.ImportKey(name, publicKeyPEM, tags); err != nil {
+		return "", err
+	}
+
+	privateKeyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(private),
+	})
+	return string(privateKeyPEM), nil
+}
+
+// DeleteKey removes name's metadata item and its tags.
+func (c *Client) DeleteKey(name string) error {
+	return c.updateMetadata(nil, func(key string) bool {
+		return key == metadataKeyFor(name) || key == metadataTagsKeyFor(name)
+	})
+}
+
+// ImportKey publishes a caller-supplied public key as a project metadata
+// item named after name, without ever generating or seeing a private key,
+// and records tags as a second metadata item alongside it.
+func (c *Client) ImportKey(name string, publicKeyPEM []byte, tags map[string]string) error {
+	updates := map[string]string{metadataKeyFor(name): string(publicKeyPEM)}
+	if len(tags) > 0 {
+		encoded, err := json.Marshal(tags)
+		if err != nil {
+			return fmt.Errorf("could not encode tags for '%s': %s", name, err)
+		}
+		updates[metadataTagsKeyFor(name)] = string(encoded)
+	}
+	return c.updateMetadata(updates, nil)
+}
+
+// updateMetadata merges updates into the project's common instance
+// metadata and removes any item whose key satisfies remove, in a single
+// read-modify-write of CommonInstanceMetadata.
+func (c *Client) updateMetadata(updates map[string]string, remove func(key string) bool) error {
+	project, err := c.Compute.Projects.Get(c.Project).Do()
+	if err != nil {
+		return fmt.Errorf("could not read project metadata: %s", err)
+	}
+
+	var items []*compute.MetadataItems
+	seen := map[string]bool{}
+	if project.CommonInstanceMetadata != nil {
+		for _, item := range project.CommonInstanceMetadata.Items {
+			if remove != nil && remove(item.Key) {
+				continue
+			}
+			if value, ok := updates[item.Key]; ok {
+				item.Value = &value
+				seen[item.Key] = true
+			}
+			items = append(items, item)
+		}
+	}
+	for key, value := range updates {
+		if seen[key] {
+			continue
+		}
+		value := value
+		items = append(items, &compute.MetadataItems{Key: key, Value: &value})
+	}
+
+	fingerprint := ""
+	if project.CommonInstanceMetadata != nil {
+		fingerprint = project.CommonInstanceMetadata.Fingerprint
+	}
+
+	_, err = c.Compute.Projects.SetCommonInstanceMetadata(c.Project, &compute.Metadata{
+		Fingerprint: fingerprint,
+		Items:       items,
+	}).Do()
+	if err != nil {
+		return fmt.Errorf("could not update project metadata: %s", err)
+	}
+	return nil
+}
+
+// KeyFingerprint returns the fingerprint of the public key currently
+// published under name, computed the same way AWS fingerprints imported
+// keys so it can be compared across providers.
+func (c *Client) KeyFingerprint(name string) (string, error) {
+	project, err := c.Compute.Projects.Get(c.Project).Do()
+	if err != nil {
+		return "", fmt.Errorf("could not read project metadata: %s", err)
+	}
+
+	key := metadataKeyFor(name)
+	if project.CommonInstanceMetadata != nil {
+		for _, item := range project.CommonInstanceMetadata.Items {
+			if item.Key == key && item.Value != nil {
+				return iaas.FingerprintForImportedKey([]byte(*item.Value))
+			}
+		}
+	}
+	return "", fmt.Errorf("no key named '%s' is registered", name)
+}
+
+// ListKeys returns the names of all registered keys whose name starts with
+// prefix.
+func (c *Client) ListKeys(prefix string) ([]string, error) {
+	project, err := c.Compute.Projects.Get(c.Project).Do()
+	if err != nil {
+		return nil, fmt.Errorf("could not read project metadata: %s", err)
+	}
+
+	var names []string
+	if project.CommonInstanceMetadata != nil {
+		for _, item := range project.CommonInstanceMetadata.Items {
+			if !strings.HasPrefix(item.Key, metadataKeyPrefix) {
+				continue
+			}
+			name := strings.TrimPrefix(item.Key, metadataKeyPrefix)
+			if strings.HasPrefix(name, prefix) {
+				names = append(names, name)
+			}
+		}
+	}
+	return names, nil
+}
+
+// Tags returns the bosh-classroom tags recorded alongside the key named
+// name.
+func (c *Client) Tags(name string) (map[string]string, error) {
+	project, err := c.Compute.Projects.Get(c.Project).Do()
+	if err != nil {
+		return nil, fmt.Errorf("could not read project metadata: %s", err)
+	}
+
+	key := metadataTagsKeyFor(name)
+	if project.CommonInstanceMetadata != nil {
+		for _, item := range project.CommonInstanceMetadata.Items {
+			if item.Key == key && item.Value != nil {
+				var tags map[string]string
+				if err := json.Unmarshal([]byte(*item.Value), &tags); err != nil {
+					return nil, fmt.Errorf("could not decode tags for '%s': %s", name, err)
+				}
+				return tags, nil
+			}
+		}
+	}
+	return map[string]string{}, nil
+}
+
+// ListClassroomsByTag returns the names of all classrooms whose tags match
+// all of filters.
+func (c *Client) ListClassroomsByTag(filters map[string]string) ([]string, error) {
+	project, err := c.Compute.Projects.Get(c.Project).Do()
+	if err != nil {
+		return nil, fmt.Errorf("could not read project metadata: %s", err)
+	}
+
+	var names []string
+	if project.CommonInstanceMetadata != nil {
+		for _, item := range project.CommonInstanceMetadata.Items {
+			if !strings.HasPrefix(item.Key, metadataTagsPrefix) || item.Value == nil {
+				continue
+			}
+			var tags map[string]string
+			if err := json.Unmarshal([]byte(*item.Value), &tags); err != nil {
+				continue
+			}
+			if classroomName, ok := tags[iaas.TagPrefix+"name"]; ok && matchesAll(tags, filters) {
+				names = append(names, classroomName)
+			}
+		}
+	}
+	return names, nil
+}
+
+func matchesAll(tags, filters map[string]string) bool {
+	for key, value := range filters {
+		if tags[iaas.TagPrefix+key] != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+)
+
+// StoreObject uploads data to c.Bucket under name.
+func (c *Client) StoreObject(name string, data []byte, downloadFileName, contentType string, tags map[string]string) error {
+	ctx := context.Background()
+	w := c.Storage.Bucket(c.Bucket).Object(name).NewWriter(ctx)
+	w.ContentType = contentType
+	w.ContentDisposition = fmt.Sprintf("attachment; filename=%q", downloadFileName)
+	w.Metadata = tags
+
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return fmt.Errorf("could not upload '%s': %s", name, err)
+	}
+	return w.Close()
+}
+
+// FetchObject downloads and returns the bytes previously stored under name.
+func (c *Client) FetchObject(name string) ([]byte, error) {
+	ctx := context.Background()
+	r, err := c.Storage.Bucket(c.Bucket).Object(name).NewReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch '%s': %s", name, err)
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+// DeleteObject removes name from c.Bucket.
+func (c *Client) DeleteObject(name string) error {
+	ctx := context.Background()
+	return c.Storage.Bucket(c.Bucket).Object(name).Delete(ctx)
+}
+
+// URLForObject returns the public GCS URL for name.
+func (c *Client) URLForObject(name string) string {
+	return fmt.Sprintf("https://storage.googleapis.com/%s/%s", c.Bucket, name)
+}
@@ -0,0 +1,43 @@
+package gcp
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Deployment Manager configs are plain YAML, so classroom parameters are
+// substituted as {{PARAM}} placeholders rather than CloudFormation-style
+// template Parameters blocks.
+var placeholderPattern = regexp.MustCompile(`{{(\w+)}}`)
+
+func substituteParameters(template string, parameters map[string]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(template, func(match string) string {
+		name := placeholderPattern.FindStringSubmatch(match)[1]
+		if value, ok := parameters[name]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// extractParameters recovers the "key: value" properties metadata Controller
+// relies on (e.g. InstanceCount) from a deployment's resolved config, so
+// DescribeStack can report them back the same way AWS's DescribeStack does
+// from stack parameters.
+func extractParameters(config string) map[string]string {
+	parameters := map[string]string{}
+	for _, line := range strings.Split(config, "\n") {
+		line = strings.TrimSpace(line)
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(parts[0])
+		value := strings.TrimSpace(parts[1])
+		if key == "" || value == "" {
+			continue
+		}
+		parameters[key] = value
+	}
+	return parameters
+}
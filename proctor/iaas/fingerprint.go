@@ -0,0 +1,38 @@
+package iaas
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// FingerprintForImportedKey computes the fingerprint a backend assigns to a
+// key pair that was imported (as opposed to generated by the provider
+// itself): the hex MD5 digest, colon-separated, of the key's SSH
+// wire-format blob (RFC 4253 section 6.6), which is what EC2 (and the
+// ssh-keygen -E md5 convention it follows) actually hashes for an imported
+// key — not the PKIX/X.509 DER encoding of the same key, which is a
+// different byte string entirely. Comparing this against KeyFingerprint
+// lets a caller confirm a local public key matches what's registered with
+// the provider without ever uploading the private half.
+func FingerprintForImportedKey(publicKeyPEM []byte) (string, error) {
+	parsed, _, _, _, err := ssh.ParseAuthorizedKey(publicKeyPEM)
+	if err != nil {
+		return "", fmt.Errorf("could not parse public key: %s", err)
+	}
+
+	sum := md5.Sum(parsed.Marshal())
+	hexDigits := hex.EncodeToString(sum[:])
+
+	fingerprint := make([]byte, 0, len(hexDigits)+len(hexDigits)/2)
+	for i := 0; i < len(hexDigits); i += 2 {
+		if i > 0 {
+			fingerprint = append(fingerprint, ':')
+		}
+		fingerprint = append(fingerprint, hexDigits[i], hexDigits[i+1])
+	}
+
+	return string(fingerprint), nil
+}
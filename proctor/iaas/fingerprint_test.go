@@ -0,0 +1,82 @@
+package iaas
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func generateAuthorizedKey(t *testing.T) []byte {
+	t.Helper()
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("could not generate key: %s", err)
+	}
+	signer, err := ssh.NewSignerFromKey(private)
+	if err != nil {
+		t.Fatalf("could not derive signer: %s", err)
+	}
+	return ssh.MarshalAuthorizedKey(signer.PublicKey())
+}
+
+// TestFingerprintForImportedKeyMatchesSSHKeygen locks the digest input down
+// against a real key pair: "ssh-keygen -lf testkey.pub -E md5" (and the
+// fingerprint EC2 itself reports for an imported key) reports
+// 47:63:59:3f:7f:85:17:cb:9b:aa:46:cf:b1:fb:18:3e for this exact public
+// key, which is MD5 of the key's SSH wire-format blob, not of its PKIX DER
+// encoding.
+func TestFingerprintForImportedKeyMatchesSSHKeygen(t *testing.T) {
+	const publicKeyPEM = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQCrGaSV5miQdjDRCosb8pUgof1SzHSHgEXGcrb3K71YJmvHv+c9KTwsXdNhkEp6EVcqiar7e44KoAfozhLPiZ9R4iV5Qtjd61A/YVKYPXI5qZmKa5HUwHUZuM9tGQi0+PK5iIovZni8V5HFIn16RqoWF3gpN3OBgSsyDziHELjsWqq+2JseknIsWJ4zpFZVc/2dIb6AFHn/z98LgZ8REJwUjnLbbI0TfYRzGV5zOczDMmD1i4Q+1kWUKhmqmnQU+WzlWq6n6bWZ3T3Op6Bwz9ujlWOqOt4OOilPZwef/KdZi5vqFIqEh4Tvs7YdJZcnn2YDzrk7NDuBx+jufSsPrCx1 root@vm"
+	const wantFingerprint = "47:63:59:3f:7f:85:17:cb:9b:aa:46:cf:b1:fb:18:3e"
+
+	got, err := FingerprintForImportedKey([]byte(publicKeyPEM))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got != wantFingerprint {
+		t.Errorf("expected ssh-keygen-compatible fingerprint %q, got %q", wantFingerprint, got)
+	}
+}
+
+func TestFingerprintForImportedKeyIsDeterministic(t *testing.T) {
+	publicKeyPEM := generateAuthorizedKey(t)
+
+	first, err := FingerprintForImportedKey(publicKeyPEM)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	second, err := FingerprintForImportedKey(publicKeyPEM)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if first != second {
+		t.Errorf("expected the same key to fingerprint the same way twice, got %q and %q", first, second)
+	}
+
+	const digestBytes = 16 // MD5
+	if want := digestBytes*2 + (digestBytes - 1); len(first) != want {
+		t.Errorf("expected a colon-separated MD5 hex digest of length %d, got %q (%d)", want, first, len(first))
+	}
+}
+
+func TestFingerprintForImportedKeyDiffersBetweenKeys(t *testing.T) {
+	a, err := FingerprintForImportedKey(generateAuthorizedKey(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	b, err := FingerprintForImportedKey(generateAuthorizedKey(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if a == b {
+		t.Error("expected two different keys to have different fingerprints")
+	}
+}
+
+func TestFingerprintForImportedKeyRejectsGarbage(t *testing.T) {
+	if _, err := FingerprintForImportedKey([]byte("not a key")); err == nil {
+		t.Error("expected an error for unparseable input")
+	}
+}
@@ -0,0 +1,54 @@
+// Package iaas defines the backend-agnostic contract Controller drives
+// classroom infrastructure through. aws, gcp, and azure each provide a
+// Client that satisfies this interface for their respective provider.
+package iaas
+
+import "time"
+
+// Client is everything Controller needs from a cloud backend: a keypair
+// it can hand to instances, a place to park the private key artifact, and
+// an infrastructure stack (CloudFormation, Deployment Manager, ARM) that
+// actually provisions the classroom's hosts.
+type Client interface {
+	CreateKey(name string, tags map[string]string) (string, error)
+	DeleteKey(name string) error
+	ImportKey(name string, publicKeyPEM []byte, tags map[string]string) error
+	KeyFingerprint(name string) (string, error)
+	ListKeys(prefix string) ([]string, error)
+	Tags(name string) (map[string]string, error)
+	StoreObject(name string, bytes []byte, downloadFileName, contentType string, tags map[string]string) error
+	FetchObject(name string) ([]byte, error)
+	DeleteObject(name string) error
+	URLForObject(name string) string
+	CreateStack(name string, template string, parameters, tags map[string]string) (string, error)
+
+	// ImageAvailable reports whether image is usable in this Client's
+	// region/project, for providers with a queryable image resource
+	// (AWS AMIs, GCP images). Backends with nothing to query always
+	// report true; ImportClassroom treats a false result as its signal
+	// to re-resolve a fresh image instead of submitting a stack that's
+	// bound to fail.
+	ImageAvailable(image string) (bool, error)
+
+	DeleteStack(name string) error
+	DescribeStack(name string) (string, string, map[string]string, error)
+	GetHostsFromStackID(stackID string) (map[string]string, error)
+	StackEventsSince(name string, since time.Time) ([]StackEvent, time.Time, error)
+
+	// ListClassroomsByTag returns the classroom names (the "name" tag's
+	// value, not the prefixed resource name) of every classroom whose tags
+	// match all of filters. filters keys are bare tag names (e.g. "owner"),
+	// not TagPrefix-qualified.
+	ListClassroomsByTag(filters map[string]string) ([]string, error)
+}
+
+// StackEvent is a single infrastructure-stack lifecycle event, generalized
+// across CloudFormation stack events, Deployment Manager operations, and
+// ARM deployment operations.
+type StackEvent struct {
+	Timestamp         time.Time
+	LogicalResourceID string
+	ResourceType      string
+	ResourceStatus    string
+	StatusReason      string
+}
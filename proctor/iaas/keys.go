@@ -0,0 +1,33 @@
+package iaas
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// PublicKeyFromPrivatePEM derives the SSH authorized_keys-format public key
+// for an RSA private key PEM-encoded the way CreateKey returns one (PKCS#1,
+// "RSA PRIVATE KEY"). This lets a private key recovered from a snapshot
+// bundle be re-registered with ImportKey without the provider ever
+// generating a new keypair.
+func PublicKeyFromPrivatePEM(privateKeyPEM []byte) ([]byte, error) {
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("could not decode private key PEM")
+	}
+
+	private, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse private key: %s", err)
+	}
+
+	signer, err := ssh.NewSignerFromKey(private)
+	if err != nil {
+		return nil, fmt.Errorf("could not derive public key: %s", err)
+	}
+
+	return ssh.MarshalAuthorizedKey(signer.PublicKey()), nil
+}
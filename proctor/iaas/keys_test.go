@@ -0,0 +1,42 @@
+package iaas
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestPublicKeyFromPrivatePEM(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("could not generate key: %s", err)
+	}
+	privateKeyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(private),
+	})
+
+	signer, err := ssh.NewSignerFromKey(private)
+	if err != nil {
+		t.Fatalf("could not derive signer: %s", err)
+	}
+	want := ssh.MarshalAuthorizedKey(signer.PublicKey())
+
+	got, err := PublicKeyFromPrivatePEM(privateKeyPEM)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("expected derived public key %q, got %q", want, got)
+	}
+}
+
+func TestPublicKeyFromPrivatePEMRejectsGarbage(t *testing.T) {
+	if _, err := PublicKeyFromPrivatePEM([]byte("not a key")); err == nil {
+		t.Error("expected an error for unparseable input")
+	}
+}
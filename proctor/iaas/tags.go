@@ -0,0 +1,35 @@
+package iaas
+
+import "time"
+
+// TagPrefix namespaces every tag bosh-classroom applies to provider
+// resources, so a classroom's keypair, stack, and stored artifact can be
+// told apart from anything else in the account and found again by
+// ListClassroomsByTag without relying on naming conventions alone.
+const TagPrefix = "bosh-classroom:"
+
+// KeySourceGenerated and KeySourceImported are the values the
+// bosh-classroom:key-source tag can take, recording whether a classroom's
+// keypair was generated by the provider and its private key uploaded
+// (KeySourceGenerated), or supplied locally via --public-key and never
+// uploaded (KeySourceImported). Every CLI invocation is its own process,
+// so this has to live in a tag rather than in-memory Controller state for
+// DescribeClassroom/DestroyClassroom to get it right from any process.
+const (
+	KeySourceGenerated = "generated"
+	KeySourceImported  = "imported"
+)
+
+// ClassroomTags builds the full set of tags applied to every resource
+// created for a classroom: its name, who created it, when, how long it may
+// live before ReapExpired considers it stale, and where its keypair came
+// from (keySource should be KeySourceGenerated or KeySourceImported).
+func ClassroomTags(name, owner string, createdAt time.Time, ttl time.Duration, keySource string) map[string]string {
+	return map[string]string{
+		TagPrefix + "name":       name,
+		TagPrefix + "owner":      owner,
+		TagPrefix + "created-at": createdAt.Format(time.RFC3339),
+		TagPrefix + "ttl":        ttl.String(),
+		TagPrefix + "key-source": keySource,
+	}
+}
@@ -0,0 +1,30 @@
+package iaas
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClassroomTags(t *testing.T) {
+	createdAt := time.Date(2026, 7, 26, 12, 0, 0, 0, time.UTC)
+	ttl := 24 * time.Hour
+
+	tags := ClassroomTags("my-class", "instructor@example.com", createdAt, ttl, KeySourceImported)
+
+	want := map[string]string{
+		TagPrefix + "name":       "my-class",
+		TagPrefix + "owner":      "instructor@example.com",
+		TagPrefix + "created-at": "2026-07-26T12:00:00Z",
+		TagPrefix + "ttl":        "24h0m0s",
+		TagPrefix + "key-source": KeySourceImported,
+	}
+
+	if len(tags) != len(want) {
+		t.Fatalf("expected %d tags, got %d: %v", len(want), len(tags), tags)
+	}
+	for key, value := range want {
+		if tags[key] != value {
+			t.Errorf("tag %q: expected %q, got %q", key, value, tags[key])
+		}
+	}
+}